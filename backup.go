@@ -2,8 +2,10 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 import (
+	"context"
 	"errors"
 	"unsafe"
 )
@@ -12,7 +14,8 @@ import (
 // in a backup engine instance. Use this to get the state of the
 // backup like number of backups and their ids and timestamps etc.
 type BackupEngineInfo struct {
-	c *C.rocksdb_backup_engine_info_t
+	c           *C.rocksdb_backup_engine_info_t
+	appMetadata []string
 }
 
 // GetCount gets the number backsup available.
@@ -41,6 +44,11 @@ func (b *BackupEngineInfo) GetNumFiles(index int) int32 {
 	return int32(C.rocksdb_backup_engine_info_number_files(b.c, C.int(index)))
 }
 
+// GetAppMetadata gets the application metadata the backup was created with.
+func (b *BackupEngineInfo) GetAppMetadata(index int) string {
+	return b.appMetadata[index]
+}
+
 // Destroy destroys the backup engine info instance.
 func (b *BackupEngineInfo) Destroy() {
 	C.rocksdb_backup_engine_info_destroy(b.c)
@@ -90,7 +98,7 @@ func OpenBackupEngine(opts *Options, path string) (*BackupEngine, error) {
 	be := C.rocksdb_backup_engine_open(opts.c, cpath, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return &BackupEngine{
 		c:    be,
@@ -99,6 +107,34 @@ func OpenBackupEngine(opts *Options, path string) (*BackupEngine, error) {
 	}, nil
 }
 
+// OpenBackupEngineWithOptions opens a backup engine with the specified
+// backup options. If env is nil, the default environment is used. Envs
+// created by NewHookedEnv are not supported.
+func OpenBackupEngineWithOptions(opts *BackupableDBOptions, env *Env) (*BackupEngine, error) {
+	var (
+		cErr *C.char
+		cEnv *C.rocksdb_env_t
+	)
+	if env != nil {
+		if env.ext != nil {
+			return nil, errors.New("the backup engine does not support hooked envs")
+		}
+		cEnv = env.c
+	} else {
+		// destroying the default env does not destroy the shared instance
+		defaultEnv := NewDefaultEnv()
+		defer defaultEnv.Destroy()
+		cEnv = defaultEnv.c
+	}
+
+	be := C.rocksdb_backup_engine_open_opts(opts.c, cEnv, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return &BackupEngine{c: be}, nil
+}
+
 // UnsafeGetBackupEngine returns the underlying c backup engine.
 func (b *BackupEngine) UnsafeGetBackupEngine() unsafe.Pointer {
 	return unsafe.Pointer(b.c)
@@ -112,7 +148,7 @@ func (b *BackupEngine) CreateNewBackupFlush(db *DB, flush bool) error {
 	C.rocksdb_backup_engine_create_new_backup_flush(b.c, db.c, boolToChar(flush), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 
 	return nil
@@ -123,12 +159,83 @@ func (b *BackupEngine) CreateNewBackup(db *DB) error {
 	return b.CreateNewBackupFlush(db, false)
 }
 
+// CreateNewBackupContext takes a new backup from db like
+// CreateNewBackupFlush. If ctx is done before the backup finished, the
+// backup is stopped and ctx.Err() is returned.
+//
+// Once a backup is stopped, no new backups can be created with the backup
+// engine; it has to be closed and opened again. The backup is not stopped if
+// it finished before ctx was done.
+func (b *BackupEngine) CreateNewBackupContext(ctx context.Context, db *DB, flush bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return runContext(ctx, func() error {
+		return b.CreateNewBackupFlush(db, flush)
+	}, func() {
+		C.gorocksdb_backup_engine_stop_backup(b.c)
+	})
+}
+
+// CreateNewBackupWithMetadata takes a new backup from db and stores the
+// application metadata with it, see BackupEngineInfo.GetAppMetadata.
+// If flush is set to true, it flushes the WAL before taking the backup.
+func (b *BackupEngine) CreateNewBackupWithMetadata(db *DB, appMetadata string, flush bool) error {
+	var cErr *C.char
+	cAppMetadata := C.CString(appMetadata)
+	defer C.free(unsafe.Pointer(cAppMetadata))
+
+	C.gorocksdb_backup_engine_create_new_backup_with_metadata(b.c, db.c, cAppMetadata, C.size_t(len(appMetadata)), boolToChar(flush), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// CreateNewBackupTransactionDB takes a new backup from a transaction db and
+// stores the application metadata with it. If flush is set to true, it
+// flushes the WAL before taking the backup.
+func (b *BackupEngine) CreateNewBackupTransactionDB(db *TransactionDB, appMetadata string, flush bool) error {
+	var cErr *C.char
+	cAppMetadata := C.CString(appMetadata)
+	defer C.free(unsafe.Pointer(cAppMetadata))
+
+	C.gorocksdb_backup_engine_create_new_backup_transactiondb(b.c, db.c, cAppMetadata, C.size_t(len(appMetadata)), boolToChar(flush), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
 // GetInfo gets an object that gives information about
 // the backups that have already been taken
 func (b *BackupEngine) GetInfo() *BackupEngineInfo {
-	return &BackupEngineInfo{
+	info := &BackupEngineInfo{
 		c: C.rocksdb_backup_engine_get_backup_info(b.c),
 	}
+	info.appMetadata = make([]string, info.GetCount())
+	for i := range info.appMetadata {
+		info.appMetadata[i] = b.getAppMetadata(uint32(info.GetBackupId(i)))
+	}
+	return info
+}
+
+// getAppMetadata returns the application metadata of the backup with the
+// given id, or an empty string if the backup does not exist anymore.
+func (b *BackupEngine) getAppMetadata(backupID uint32) string {
+	var (
+		cErr *C.char
+		cLen C.size_t
+	)
+	cAppMetadata := C.gorocksdb_backup_engine_get_app_metadata(b.c, C.uint32_t(backupID), &cLen, &cErr)
+	if cErr != nil {
+		C.rocksdb_free(unsafe.Pointer(cErr))
+		return ""
+	}
+	defer C.free(unsafe.Pointer(cAppMetadata))
+	return C.GoStringN(cAppMetadata, C.int(cLen))
 }
 
 // RestoreDBFromLatestBackup restores the latest backup to dbDir. walDir
@@ -145,7 +252,49 @@ func (b *BackupEngine) RestoreDBFromLatestBackup(dbDir, walDir string, ro *Resto
 	C.rocksdb_backup_engine_restore_db_from_latest_backup(b.c, cDbDir, cWalDir, ro.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// RestoreDBFromBackup restores the backup with the given id to dbDir. walDir
+// is where the write ahead logs are restored to and usually the same as dbDir.
+func (b *BackupEngine) RestoreDBFromBackup(backupID uint32, dbDir, walDir string, ro *RestoreOptions) error {
+	var cErr *C.char
+	cDbDir := C.CString(dbDir)
+	cWalDir := C.CString(walDir)
+	defer func() {
+		C.free(unsafe.Pointer(cDbDir))
+		C.free(unsafe.Pointer(cWalDir))
+	}()
+
+	C.rocksdb_backup_engine_restore_db_from_backup(b.c, cDbDir, cWalDir, ro.c, C.uint32_t(backupID), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// VerifyBackup checks that the files of the backup with the given id exist
+// and have the expected sizes.
+func (b *BackupEngine) VerifyBackup(backupID uint32) error {
+	var cErr *C.char
+	C.rocksdb_backup_engine_verify_backup(b.c, C.uint32_t(backupID), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// DeleteBackup deletes the backup with the given id.
+func (b *BackupEngine) DeleteBackup(backupID uint32) error {
+	var cErr *C.char
+	C.gorocksdb_backup_engine_delete_backup(b.c, C.uint32_t(backupID), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -156,7 +305,7 @@ func (b *BackupEngine) PurgeOldBackups(n uint32) error {
 	C.rocksdb_backup_engine_purge_old_backups(b.c, C.uint32_t(n), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -0,0 +1,467 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BackupStorage is a store for backup files, e.g. an object store.
+// Names are slash separated paths.
+type BackupStorage interface {
+	// Put stores the content of r under name, replacing existing content.
+	Put(name string, r io.Reader) error
+
+	// Get returns the content stored under name. If there is none, the
+	// error satisfies errors.Is(err, os.ErrNotExist).
+	Get(name string) (io.ReadCloser, error)
+
+	// List returns the sorted names starting with prefix.
+	List(prefix string) ([]string, error)
+
+	// Delete removes the content stored under name.
+	Delete(name string) error
+}
+
+const (
+	backupStorageObjectPrefix   = "objects/"
+	backupStorageSnapshotPrefix = "snapshots/"
+)
+
+// BackupSyncStats describes the work done by SyncToBackupStorage and
+// RestoreFromBackupStorage.
+type BackupSyncStats struct {
+	// Files is the number of files in the snapshot.
+	Files int
+	// TransferredFiles is the number of files which were copied, the
+	// other files were already present.
+	TransferredFiles int
+	// TransferredBytes is the size of the copied files.
+	TransferredBytes int64
+}
+
+type backupSnapshotFile struct {
+	Path string `json:"path"`
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
+type backupSnapshot struct {
+	Files []backupSnapshotFile `json:"files"`
+}
+
+// SyncToBackupStorage stores the files in dir, e.g. the directory of a
+// BackupEngine or a Checkpoint, as snapshot in storage. Files are stored
+// by content hash, so only files which are not yet part of any snapshot
+// are uploaded.
+func SyncToBackupStorage(dir string, storage BackupStorage, snapshot string) (BackupSyncStats, error) {
+	var stats BackupSyncStats
+	if !validBackupSnapshotName(snapshot) {
+		return stats, errInvalidBackupStorageName
+	}
+
+	existing, err := storage.List(backupStorageObjectPrefix)
+	if err != nil {
+		return stats, err
+	}
+	stored := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		stored[name] = true
+	}
+
+	var s backupSnapshot
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		hash, err := hashBackupFile(p)
+		if err != nil {
+			return err
+		}
+		s.Files = append(s.Files, backupSnapshotFile{filepath.ToSlash(rel), hash, info.Size()})
+
+		name := backupStorageObjectPrefix + hash
+		if stored[name] {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := storage.Put(name, f); err != nil {
+			return err
+		}
+		stored[name] = true
+		stats.TransferredFiles++
+		stats.TransferredBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+	stats.Files = len(s.Files)
+
+	// the snapshot is written last, so it only references stored files
+	data, err := json.Marshal(&s)
+	if err != nil {
+		return stats, err
+	}
+	return stats, storage.Put(backupStorageSnapshotPrefix+snapshot, bytes.NewReader(data))
+}
+
+// RestoreFromBackupStorage restores snapshot from storage into dir. Files
+// already present in dir with the expected content are not downloaded.
+// Files in dir which are not part of the snapshot are left untouched.
+func RestoreFromBackupStorage(storage BackupStorage, snapshot string, dir string) (BackupSyncStats, error) {
+	var stats BackupSyncStats
+
+	s, err := readBackupSnapshot(storage, snapshot)
+	if err != nil {
+		return stats, err
+	}
+	stats.Files = len(s.Files)
+
+	// the snapshot is read from storage, check all entries before writing
+	paths := make([]string, len(s.Files))
+	for i, file := range s.Files {
+		if paths[i], err = backupSnapshotFilePath(dir, file); err != nil {
+			return stats, err
+		}
+	}
+
+	for i, file := range s.Files {
+		p := paths[i]
+		if hash, err := hashBackupFile(p); err == nil && hash == file.Hash {
+			continue
+		}
+		if err := restoreBackupFile(storage, backupStorageObjectPrefix+file.Hash, p); err != nil {
+			return stats, err
+		}
+		stats.TransferredFiles++
+		stats.TransferredBytes += file.Size
+	}
+	return stats, nil
+}
+
+// ListBackupSnapshots returns the sorted names of the snapshots in storage.
+func ListBackupSnapshots(storage BackupStorage) ([]string, error) {
+	names, err := storage.List(backupStorageSnapshotPrefix)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range names {
+		names[i] = strings.TrimPrefix(name, backupStorageSnapshotPrefix)
+	}
+	return names, nil
+}
+
+// DeleteBackupSnapshot deletes snapshot from storage and then removes the
+// stored files which are not part of any other snapshot. It must not run
+// concurrently with SyncToBackupStorage on the same storage, which could
+// lose files that are uploaded but not yet referenced.
+func DeleteBackupSnapshot(storage BackupStorage, snapshot string) error {
+	if !validBackupSnapshotName(snapshot) {
+		return errInvalidBackupStorageName
+	}
+	if err := storage.Delete(backupStorageSnapshotPrefix + snapshot); err != nil {
+		return err
+	}
+	_, err := CollectBackupStorageGarbage(storage)
+	return err
+}
+
+// CollectBackupStorageGarbage removes the stored files which are not part
+// of any snapshot, e.g. those left behind by an interrupted
+// DeleteBackupSnapshot or SyncToBackupStorage. It returns the number of
+// removed files and has the same restrictions as DeleteBackupSnapshot.
+func CollectBackupStorageGarbage(storage BackupStorage) (int, error) {
+	snapshots, err := ListBackupSnapshots(storage)
+	if err != nil {
+		return 0, err
+	}
+	referenced := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		s, err := readBackupSnapshot(storage, snapshot)
+		if err != nil {
+			return 0, err
+		}
+		for _, file := range s.Files {
+			referenced[backupStorageObjectPrefix+file.Hash] = true
+		}
+	}
+
+	objects, err := storage.List(backupStorageObjectPrefix)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, name := range objects {
+		if referenced[name] {
+			continue
+		}
+		if err := storage.Delete(name); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func readBackupSnapshot(storage BackupStorage, snapshot string) (*backupSnapshot, error) {
+	if !validBackupSnapshotName(snapshot) {
+		return nil, errInvalidBackupStorageName
+	}
+	r, err := storage.Get(backupStorageSnapshotPrefix + snapshot)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var s backupSnapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+var errInvalidBackupSnapshot = errors.New("invalid backup snapshot")
+
+// backupSnapshotFilePath returns the path of file below dir. It fails if the
+// file has an invalid path or hash, so a corrupt snapshot can not write
+// outside of dir.
+func backupSnapshotFilePath(dir string, file backupSnapshotFile) (string, error) {
+	if !validBackupStorageName(file.Path) || !validBackupFileHash(file.Hash) {
+		return "", errInvalidBackupSnapshot
+	}
+	root := filepath.Clean(dir)
+	p := filepath.Join(root, filepath.FromSlash(file.Path))
+	rel, err := filepath.Rel(root, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errInvalidBackupSnapshot
+	}
+	return p, nil
+}
+
+// validBackupFileHash reports whether hash is a hex encoded sha256 as
+// returned by hashBackupFile.
+func validBackupFileHash(hash string) bool {
+	if len(hash) != hex.EncodedLen(sha256.Size) {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+func hashBackupFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// restoreBackupFile downloads name into a temporary file which is renamed
+// to p, so p never has partial content.
+func restoreBackupFile(storage BackupStorage, name, p string) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	r, err := storage.Get(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return writeFileAtomic(p, r)
+}
+
+func writeFileAtomic(p string, r io.Reader) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(p), "."+filepath.Base(p)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// LocalBackupStorage is a BackupStorage which stores the files in a
+// directory of the local filesystem.
+type LocalBackupStorage struct {
+	root string
+}
+
+// NewLocalBackupStorage creates a LocalBackupStorage storing the files
+// below root.
+func NewLocalBackupStorage(root string) *LocalBackupStorage {
+	return &LocalBackupStorage{root: root}
+}
+
+var errInvalidBackupStorageName = errors.New("invalid backup storage name")
+
+// validBackupStorageName reports whether name is a clean, relative and
+// non-empty slash separated path.
+func validBackupStorageName(name string) bool {
+	clean := path.Clean("/" + name)
+	return clean != "/" && clean == "/"+name
+}
+
+// validBackupSnapshotName reports whether snapshot is a valid storage name
+// without slashes, so it is listed by ListBackupSnapshots.
+func validBackupSnapshotName(snapshot string) bool {
+	return validBackupStorageName(snapshot) && !strings.Contains(snapshot, "/")
+}
+
+func (s *LocalBackupStorage) path(name string) (string, error) {
+	if !validBackupStorageName(name) {
+		return "", errInvalidBackupStorageName
+	}
+	return filepath.Join(s.root, filepath.FromSlash(name)), nil
+}
+
+// Put implements BackupStorage.
+func (s *LocalBackupStorage) Put(name string, r io.Reader) error {
+	p, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(p, r)
+}
+
+// Get implements BackupStorage.
+func (s *LocalBackupStorage) Get(name string) (io.ReadCloser, error) {
+	p, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+// List implements BackupStorage.
+func (s *LocalBackupStorage) List(prefix string) ([]string, error) {
+	var names []string
+	err := filepath.Walk(s.root, func(p string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && p == s.root {
+			return filepath.SkipDir
+		}
+		if err != nil || info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return err
+		}
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		if name := filepath.ToSlash(rel); strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	sort.Strings(names)
+	return names, err
+}
+
+// Delete implements BackupStorage.
+func (s *LocalBackupStorage) Delete(name string) error {
+	p, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+// MemoryBackupStorage is a BackupStorage which keeps the files in memory.
+// It is intended for tests.
+type MemoryBackupStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// NewMemoryBackupStorage creates an empty MemoryBackupStorage.
+func NewMemoryBackupStorage() *MemoryBackupStorage {
+	return &MemoryBackupStorage{files: make(map[string][]byte)}
+}
+
+// Put implements BackupStorage.
+func (s *MemoryBackupStorage) Put(name string, r io.Reader) error {
+	if !validBackupStorageName(name) {
+		return errInvalidBackupStorageName
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[name] = data
+	return nil
+}
+
+// Get implements BackupStorage.
+func (s *MemoryBackupStorage) Get(name string) (io.ReadCloser, error) {
+	if !validBackupStorageName(name) {
+		return nil, errInvalidBackupStorageName
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "get", Path: name, Err: os.ErrNotExist}
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// List implements BackupStorage.
+func (s *MemoryBackupStorage) List(prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.files {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete implements BackupStorage.
+func (s *MemoryBackupStorage) Delete(name string) error {
+	if !validBackupStorageName(name) {
+		return errInvalidBackupStorageName
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.files[name]; !ok {
+		return &os.PathError{Op: "delete", Path: name, Err: os.ErrNotExist}
+	}
+	delete(s.files, name)
+	return nil
+}
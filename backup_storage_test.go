@@ -0,0 +1,169 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestBackupStorageSync(t *testing.T) {
+	for name, newStorage := range map[string]func(t *testing.T) BackupStorage{
+		"memory": func(t *testing.T) BackupStorage { return NewMemoryBackupStorage() },
+		"local": func(t *testing.T) BackupStorage {
+			root, err := ioutil.TempDir("", "gorocksdb-TestBackupStorageSync-storage")
+			ensure.Nil(t, err)
+			return NewLocalBackupStorage(root)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			storage := newStorage(t)
+			if local, ok := storage.(*LocalBackupStorage); ok {
+				defer os.RemoveAll(local.root)
+			}
+
+			dir, err := ioutil.TempDir("", "gorocksdb-TestBackupStorageSync")
+			ensure.Nil(t, err)
+			defer os.RemoveAll(dir)
+			writeTestFile(t, dir, "shared/1.sst", "sst1")
+			writeTestFile(t, dir, "meta/1", "meta1")
+
+			stats, err := SyncToBackupStorage(dir, storage, "1")
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, stats, BackupSyncStats{Files: 2, TransferredFiles: 2, TransferredBytes: 9})
+
+			// only the new file is uploaded
+			writeTestFile(t, dir, "shared/2.sst", "sst2")
+			writeTestFile(t, dir, "meta/2", "meta1")
+			stats, err = SyncToBackupStorage(dir, storage, "2")
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, stats, BackupSyncStats{Files: 4, TransferredFiles: 1, TransferredBytes: 4})
+
+			snapshots, err := ListBackupSnapshots(storage)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, snapshots, []string{"1", "2"})
+
+			// only the missing and changed files are downloaded
+			restoreDir, err := ioutil.TempDir("", "gorocksdb-TestBackupStorageSync-restore")
+			ensure.Nil(t, err)
+			defer os.RemoveAll(restoreDir)
+			writeTestFile(t, restoreDir, "shared/1.sst", "sst1")
+			writeTestFile(t, restoreDir, "meta/1", "changed")
+			stats, err = RestoreFromBackupStorage(storage, "2", restoreDir)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, stats, BackupSyncStats{Files: 4, TransferredFiles: 3, TransferredBytes: 14})
+			for file, content := range map[string]string{
+				"shared/1.sst": "sst1",
+				"shared/2.sst": "sst2",
+				"meta/1":       "meta1",
+				"meta/2":       "meta1",
+			} {
+				data, err := ioutil.ReadFile(filepath.Join(restoreDir, filepath.FromSlash(file)))
+				ensure.Nil(t, err)
+				ensure.DeepEqual(t, string(data), content)
+			}
+
+			_, err = RestoreFromBackupStorage(storage, "3", restoreDir)
+			ensure.True(t, errors.Is(err, os.ErrNotExist))
+		})
+	}
+}
+
+func TestBackupStorageInvalidName(t *testing.T) {
+	for _, storage := range []BackupStorage{NewLocalBackupStorage(os.TempDir()), NewMemoryBackupStorage()} {
+		for _, name := range []string{"", "../escape", "a/../../escape", "/abs", "a//b"} {
+			ensure.DeepEqual(t, storage.Put(name, bytes.NewReader(nil)), errInvalidBackupStorageName)
+			_, err := storage.Get(name)
+			ensure.DeepEqual(t, err, errInvalidBackupStorageName)
+			ensure.DeepEqual(t, storage.Delete(name), errInvalidBackupStorageName)
+		}
+		_, err := SyncToBackupStorage(os.TempDir(), storage, "a/b")
+		ensure.DeepEqual(t, err, errInvalidBackupStorageName)
+		ensure.DeepEqual(t, DeleteBackupSnapshot(storage, "../a"), errInvalidBackupStorageName)
+	}
+}
+
+func TestDeleteBackupSnapshot(t *testing.T) {
+	storage := NewMemoryBackupStorage()
+	dir, err := ioutil.TempDir("", "gorocksdb-TestDeleteBackupSnapshot")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestFile(t, dir, "shared/1.sst", "sst1")
+	_, err = SyncToBackupStorage(dir, storage, "1")
+	ensure.Nil(t, err)
+	writeTestFile(t, dir, "shared/2.sst", "sst2")
+	_, err = SyncToBackupStorage(dir, storage, "2")
+	ensure.Nil(t, err)
+	ensure.Nil(t, os.Remove(filepath.Join(dir, "shared", "1.sst")))
+	_, err = SyncToBackupStorage(dir, storage, "3")
+	ensure.Nil(t, err)
+
+	// the files of snapshot 1 are still referenced by snapshot 2
+	ensure.Nil(t, DeleteBackupSnapshot(storage, "1"))
+	objects, err := storage.List(backupStorageObjectPrefix)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(objects), 2)
+
+	// sst1 is only referenced by snapshot 2
+	ensure.Nil(t, DeleteBackupSnapshot(storage, "2"))
+	objects, err = storage.List(backupStorageObjectPrefix)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(objects), 1)
+	snapshots, err := ListBackupSnapshots(storage)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, snapshots, []string{"3"})
+
+	restoreDir, err := ioutil.TempDir("", "gorocksdb-TestDeleteBackupSnapshot-restore")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(restoreDir)
+	_, err = RestoreFromBackupStorage(storage, "3", restoreDir)
+	ensure.Nil(t, err)
+
+	_, err = RestoreFromBackupStorage(storage, "1", restoreDir)
+	ensure.True(t, errors.Is(err, os.ErrNotExist))
+
+	// unreferenced files are collected
+	ensure.Nil(t, storage.Put(backupStorageObjectPrefix+"orphan", bytes.NewReader(nil)))
+	removed, err := CollectBackupStorageGarbage(storage)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, removed, 1)
+}
+
+func TestRestoreInvalidBackupSnapshot(t *testing.T) {
+	storage := NewMemoryBackupStorage()
+	dir, err := ioutil.TempDir("", "gorocksdb-TestRestoreInvalidBackupSnapshot")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+	restoreDir := filepath.Join(dir, "restore")
+
+	const hash = "0b4f7ab7d8c4e0c9d1e3a0c2b37c0e2a52f4f3a1c8e2a1b7c6d5e4f3a2b1c0d9"
+	ensure.Nil(t, storage.Put(backupStorageObjectPrefix+hash, bytes.NewReader([]byte("evil"))))
+	for _, file := range []string{
+		`{"path": "../escape", "hash": "` + hash + `"}`,
+		`{"path": "a/../../escape", "hash": "` + hash + `"}`,
+		`{"path": "/escape", "hash": "` + hash + `"}`,
+		`{"path": "ok", "hash": "../snapshots/1"}`,
+	} {
+		snapshot := `{"files": [{"path": "ok", "hash": "` + hash + `"}, ` + file + `]}`
+		ensure.Nil(t, storage.Put(backupStorageSnapshotPrefix+"1", bytes.NewReader([]byte(snapshot))))
+		_, err = RestoreFromBackupStorage(storage, "1", restoreDir)
+		ensure.DeepEqual(t, err, errInvalidBackupSnapshot)
+	}
+
+	// nothing was written
+	_, err = os.Stat(restoreDir)
+	ensure.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "escape"))
+	ensure.True(t, os.IsNotExist(err))
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) {
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	ensure.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+	ensure.Nil(t, ioutil.WriteFile(p, []byte(content), 0644))
+}
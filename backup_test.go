@@ -0,0 +1,68 @@
+package gorocksdb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestBackupEngine(t *testing.T) {
+	db := newTestDB(t, "TestBackupEngine", nil)
+	defer db.Close()
+
+	backupDir, err := ioutil.TempDir("", "gorocksdb-TestBackupEngine-backup")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(backupDir)
+
+	opts := NewBackupableDBOptions(backupDir)
+	defer opts.Destroy()
+	opts.SetShareTableFiles(true)
+	opts.SetSync(false)
+	opts.SetMaxBackgroundOperations(2)
+	be, err := OpenBackupEngineWithOptions(opts, nil)
+	ensure.Nil(t, err)
+	defer be.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("v1")))
+	ensure.Nil(t, be.CreateNewBackupWithMetadata(db, "first", true))
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("v2")))
+	ensure.Nil(t, be.CreateNewBackupWithMetadata(db, "second", true))
+
+	info := be.GetInfo()
+	ensure.DeepEqual(t, info.GetCount(), 2)
+	ensure.DeepEqual(t, info.GetAppMetadata(0), "first")
+	ensure.DeepEqual(t, info.GetAppMetadata(1), "second")
+	firstID := uint32(info.GetBackupId(0))
+	secondID := uint32(info.GetBackupId(1))
+	info.Destroy()
+
+	ensure.Nil(t, be.VerifyBackup(firstID))
+
+	// restore the first, not the latest, backup
+	restoreDir, err := ioutil.TempDir("", "gorocksdb-TestBackupEngine-restore")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(restoreDir)
+	ro := NewRestoreOptions()
+	defer ro.Destroy()
+	ensure.Nil(t, be.RestoreDBFromBackup(firstID, restoreDir, restoreDir, ro))
+
+	restoreOpts := NewDefaultOptions()
+	defer restoreOpts.Destroy()
+	restored, err := OpenDb(restoreOpts, restoreDir)
+	ensure.Nil(t, err)
+	v, err := restored.Get(NewDefaultReadOptions(), []byte("key"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("v1"))
+	v.Free()
+	restored.Close()
+
+	ensure.Nil(t, be.DeleteBackup(firstID))
+	ensure.NotNil(t, be.VerifyBackup(firstID))
+	info = be.GetInfo()
+	defer info.Destroy()
+	ensure.DeepEqual(t, info.GetCount(), 1)
+	ensure.DeepEqual(t, uint32(info.GetBackupId(0)), secondID)
+}
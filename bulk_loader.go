@@ -0,0 +1,447 @@
+package gorocksdb
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+var (
+	errBulkLoaderClosed       = errors.New("bulk loader is closed")
+	errBulkLoaderNoComparator = errors.New("the comparator of the options is not implemented in go, set BulkLoaderOptions.Comparator")
+)
+
+// BulkLoaderOptions configures a BulkLoader. The zero value uses sensible
+// defaults for every field.
+type BulkLoaderOptions struct {
+	// Comparator is used to sort the keys. It must order keys like the
+	// comparator of the target column family.
+	// Default: the comparator of Options, or lexicographic byte-wise
+	// ordering if Options use the default comparator
+	Comparator Comparator
+
+	// Options are used to create the sst files. They must use the same
+	// comparator as the target column family.
+	// Default: the options the column family was opened or created with,
+	// or the options of the database if they are unknown. They must not be
+	// destroyed while the loader is used.
+	Options *Options
+
+	// IngestOptions are used to ingest the created files.
+	// Default: NewDefaultIngestExternalFileOptions()
+	IngestOptions *IngestExternalFileOptions
+
+	// TempDir is the directory in which temporary files are created.
+	// Default: os.TempDir()
+	TempDir string
+
+	// MemoryLimit is the number of key and value bytes buffered in memory
+	// before they are sorted and spilled to a temporary file.
+	// Default: 64MB
+	MemoryLimit int
+
+	// TargetFileSize is the number of key and value bytes after which a new
+	// sst file is started.
+	// Default: 64MB
+	TargetFileSize int
+
+	// Parallelism is the number of sst files written concurrently. Up to
+	// twice as many files worth of entries are held in memory while writing.
+	// Default: runtime.NumCPU()
+	Parallelism int
+}
+
+// BulkLoader loads unsorted key-values into a column family by writing
+// them into sorted, non-overlapping sst files which are then ingested
+// atomically.
+//
+// If the same key is added more than once, the last value wins.
+// A BulkLoader is not safe for concurrent use.
+type BulkLoader struct {
+	db   *DB
+	cf   *ColumnFamilyHandle
+	opts BulkLoaderOptions
+	cmp  func(a, b []byte) int
+
+	dir     string
+	entries []bulkLoaderEntry
+	memSize int
+	runs    []string
+}
+
+type bulkLoaderEntry struct {
+	key, value []byte
+}
+
+// NewBulkLoader creates a BulkLoader which ingests into the given column
+// family, or into the default column family if cf is nil.
+func NewBulkLoader(db *DB, cf *ColumnFamilyHandle, opts BulkLoaderOptions) (*BulkLoader, error) {
+	l := &BulkLoader{db: db, cf: cf, opts: opts, cmp: bytes.Compare}
+	if opts.Options == nil {
+		l.opts.Options = db.opts
+		if cf != nil && cf.opts != nil {
+			l.opts.Options = cf.opts
+		}
+	}
+	switch {
+	case opts.Comparator != nil:
+		l.cmp = opts.Comparator.Compare
+	case l.opts.Options.cmp != nil:
+		l.cmp = l.opts.Options.cmp.Compare
+	case l.opts.Options.ccmp != nil:
+		return nil, errBulkLoaderNoComparator
+	}
+	if opts.MemoryLimit <= 0 {
+		l.opts.MemoryLimit = 64 << 20
+	}
+	if opts.TargetFileSize <= 0 {
+		l.opts.TargetFileSize = 64 << 20
+	}
+	if opts.Parallelism <= 0 {
+		l.opts.Parallelism = runtime.NumCPU()
+	}
+
+	dir, err := ioutil.TempDir(opts.TempDir, "gorocksdb-bulkload")
+	if err != nil {
+		return nil, err
+	}
+	l.dir = dir
+	return l, nil
+}
+
+// Add adds a key-value to the loader. The key and value are copied.
+// On error the temporary files are removed and the loader is closed.
+func (l *BulkLoader) Add(key, value []byte) error {
+	if l.dir == "" {
+		return errBulkLoaderClosed
+	}
+	buf := make([]byte, len(key)+len(value))
+	copy(buf, key)
+	copy(buf[len(key):], value)
+	l.entries = append(l.entries, bulkLoaderEntry{buf[:len(key):len(key)], buf[len(key):]})
+	l.memSize += len(buf)
+	if l.memSize >= l.opts.MemoryLimit {
+		if err := l.spill(); err != nil {
+			l.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+// Finish writes the sst files, ingests them and closes the loader.
+// The temporary files are removed whether or not the load succeeds.
+func (l *BulkLoader) Finish() (err error) {
+	if l.dir == "" {
+		return errBulkLoaderClosed
+	}
+	defer func() {
+		if cErr := l.Close(); err == nil {
+			err = cErr
+		}
+	}()
+
+	l.sortEntries()
+	runs := make([]bulkLoaderRun, 0, len(l.runs)+1)
+	for _, path := range l.runs {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		runs = append(runs, &bulkLoaderFileRun{bufio.NewReader(f)})
+	}
+	// the in-memory entries are the newest run
+	runs = append(runs, &bulkLoaderMemRun{entries: l.entries})
+
+	files, err := l.writeFiles(runs)
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	ingestOpts := l.opts.IngestOptions
+	if ingestOpts == nil {
+		ingestOpts = NewDefaultIngestExternalFileOptions()
+		defer ingestOpts.Destroy()
+	}
+	if l.cf != nil {
+		return l.db.IngestExternalFileCF(l.cf, files, ingestOpts)
+	}
+	return l.db.IngestExternalFile(files, ingestOpts)
+}
+
+// Close removes the temporary files of the loader. It only needs to be
+// called if a load is abandoned before Finish is called.
+func (l *BulkLoader) Close() error {
+	if l.dir == "" {
+		return nil
+	}
+	err := os.RemoveAll(l.dir)
+	l.dir = ""
+	l.entries = nil
+	l.runs = nil
+	return err
+}
+
+// sortEntries sorts the buffered entries and drops all but the last
+// added value of duplicate keys.
+func (l *BulkLoader) sortEntries() {
+	sort.SliceStable(l.entries, func(i, j int) bool {
+		return l.cmp(l.entries[i].key, l.entries[j].key) < 0
+	})
+	n := 0
+	for i, e := range l.entries {
+		if i+1 < len(l.entries) && l.cmp(e.key, l.entries[i+1].key) == 0 {
+			continue
+		}
+		l.entries[n] = e
+		n++
+	}
+	l.entries = l.entries[:n]
+}
+
+// spill writes the sorted buffered entries into a temporary file. Each
+// entry is stored as uvarint key length, uvarint value length, key, value.
+func (l *BulkLoader) spill() error {
+	l.sortEntries()
+	path := filepath.Join(l.dir, fmt.Sprintf("run-%06d", len(l.runs)))
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	var header [2 * binary.MaxVarintLen64]byte
+	for _, e := range l.entries {
+		n := binary.PutUvarint(header[:], uint64(len(e.key)))
+		n += binary.PutUvarint(header[n:], uint64(len(e.value)))
+		w.Write(header[:n])
+		w.Write(e.key)
+		w.Write(e.value)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	l.runs = append(l.runs, path)
+	l.entries = nil
+	l.memSize = 0
+	return nil
+}
+
+// writeFiles merges the runs and writes them into sst files of about
+// TargetFileSize bytes using Parallelism concurrent writers.
+func (l *BulkLoader) writeFiles(runs []bulkLoaderRun) ([]string, error) {
+	type chunk struct {
+		path    string
+		entries []bulkLoaderEntry
+	}
+	var (
+		chunks   = make(chan chunk, l.opts.Parallelism)
+		done     = make(chan struct{})
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		writeErr error
+	)
+	setErr := func(err error) {
+		errOnce.Do(func() {
+			writeErr = err
+			close(done)
+		})
+	}
+	for i := 0; i < l.opts.Parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range chunks {
+				select {
+				case <-done:
+					continue
+				default:
+				}
+				if err := l.writeFile(c.path, c.entries); err != nil {
+					setErr(err)
+				}
+			}
+		}()
+	}
+
+	var (
+		files   []string
+		current []bulkLoaderEntry
+		size    int
+	)
+	flush := func() error {
+		path := filepath.Join(l.dir, fmt.Sprintf("%06d.sst", len(files)))
+		files = append(files, path)
+		select {
+		case chunks <- chunk{path, current}:
+		case <-done:
+			return errBulkLoaderClosed
+		}
+		current = nil
+		size = 0
+		return nil
+	}
+	err := mergeBulkLoaderRuns(runs, l.cmp, func(e bulkLoaderEntry) error {
+		current = append(current, e)
+		size += len(e.key) + len(e.value)
+		if size >= l.opts.TargetFileSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && len(current) > 0 {
+		err = flush()
+	}
+	close(chunks)
+	wg.Wait()
+
+	// a failed writer is the cause of a failed flush
+	if writeErr != nil {
+		return nil, writeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (l *BulkLoader) writeFile(path string, entries []bulkLoaderEntry) error {
+	envOpts := NewDefaultEnvOptions()
+	defer envOpts.Destroy()
+	w := NewSSTFileWriter(envOpts, l.opts.Options)
+	defer w.Destroy()
+
+	if err := w.Open(path); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := w.Put(e.key, e.value); err != nil {
+			return err
+		}
+	}
+	return w.Finish()
+}
+
+// bulkLoaderRun is a sorted sequence of entries without duplicate keys.
+type bulkLoaderRun interface {
+	next() (e bulkLoaderEntry, ok bool, err error)
+}
+
+type bulkLoaderMemRun struct {
+	entries []bulkLoaderEntry
+	pos     int
+}
+
+func (r *bulkLoaderMemRun) next() (bulkLoaderEntry, bool, error) {
+	if r.pos >= len(r.entries) {
+		return bulkLoaderEntry{}, false, nil
+	}
+	r.pos++
+	return r.entries[r.pos-1], true, nil
+}
+
+type bulkLoaderFileRun struct {
+	r *bufio.Reader
+}
+
+func (r *bulkLoaderFileRun) next() (bulkLoaderEntry, bool, error) {
+	keyLen, err := binary.ReadUvarint(r.r)
+	if err == io.EOF {
+		return bulkLoaderEntry{}, false, nil
+	} else if err != nil {
+		return bulkLoaderEntry{}, false, err
+	}
+	valueLen, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return bulkLoaderEntry{}, false, err
+	}
+	buf := make([]byte, keyLen+valueLen)
+	if _, err := io.ReadFull(r.r, buf); err != nil {
+		return bulkLoaderEntry{}, false, err
+	}
+	return bulkLoaderEntry{buf[:keyLen:keyLen], buf[keyLen:]}, true, nil
+}
+
+type bulkLoaderHeapItem struct {
+	entry bulkLoaderEntry
+	run   int
+}
+
+// bulkLoaderHeap orders equal keys by descending run, so the entry of the
+// newest run is popped first.
+type bulkLoaderHeap struct {
+	items []bulkLoaderHeapItem
+	cmp   func(a, b []byte) int
+}
+
+func (h *bulkLoaderHeap) Len() int { return len(h.items) }
+func (h *bulkLoaderHeap) Less(i, j int) bool {
+	if c := h.cmp(h.items[i].entry.key, h.items[j].entry.key); c != 0 {
+		return c < 0
+	}
+	return h.items[i].run > h.items[j].run
+}
+func (h *bulkLoaderHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *bulkLoaderHeap) Push(x interface{}) { h.items = append(h.items, x.(bulkLoaderHeapItem)) }
+func (h *bulkLoaderHeap) Pop() interface{} {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
+
+// mergeBulkLoaderRuns calls emit for every distinct key of the runs in
+// sorted order, using the value of the newest run containing the key.
+func mergeBulkLoaderRuns(runs []bulkLoaderRun, cmp func(a, b []byte) int, emit func(bulkLoaderEntry) error) error {
+	h := &bulkLoaderHeap{cmp: cmp}
+	for i, r := range runs {
+		e, ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.items = append(h.items, bulkLoaderHeapItem{e, i})
+		}
+	}
+	heap.Init(h)
+
+	var (
+		last    []byte
+		emitted bool
+	)
+	for h.Len() > 0 {
+		item := h.items[0]
+		if !emitted || cmp(item.entry.key, last) != 0 {
+			if err := emit(item.entry); err != nil {
+				return err
+			}
+			last = item.entry.key
+			emitted = true
+		}
+		e, ok, err := runs[item.run].next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.items[0] = bulkLoaderHeapItem{e, item.run}
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestBulkLoader(t *testing.T) {
+	db := newTestDB(t, "TestBulkLoader", nil)
+	defer db.Close()
+
+	tempDir, err := ioutil.TempDir("", "gorocksdb-TestBulkLoader")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(tempDir)
+
+	l, err := NewBulkLoader(db, nil, BulkLoaderOptions{
+		TempDir:        tempDir,
+		MemoryLimit:    1 << 10,
+		TargetFileSize: 4 << 10,
+		Parallelism:    4,
+	})
+	ensure.Nil(t, err)
+
+	const n = 1000
+	for _, i := range rand.Perm(n) {
+		ensure.Nil(t, l.Add([]byte(fmt.Sprintf("key%04d", i)), []byte("old")))
+	}
+	// later values win, even across spilled runs
+	for i := 0; i < n; i += 2 {
+		ensure.Nil(t, l.Add([]byte(fmt.Sprintf("key%04d", i)), []byte(fmt.Sprintf("val%04d", i))))
+	}
+	ensure.Nil(t, l.Finish())
+
+	// the temporary files are removed
+	files, err := ioutil.ReadDir(tempDir)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(files), 0)
+	ensure.DeepEqual(t, l.Add([]byte("key"), nil), errBulkLoaderClosed)
+
+	ro := NewDefaultReadOptions()
+	it := db.NewIterator(ro)
+	defer it.Close()
+	i := 0
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		ensure.DeepEqual(t, it.Key().Data(), []byte(fmt.Sprintf("key%04d", i)))
+		if i%2 == 0 {
+			ensure.DeepEqual(t, it.Value().Data(), []byte(fmt.Sprintf("val%04d", i)))
+		} else {
+			ensure.DeepEqual(t, it.Value().Data(), []byte("old"))
+		}
+		i++
+	}
+	ensure.Nil(t, it.Err())
+	ensure.DeepEqual(t, i, n)
+}
+
+func TestBulkLoaderClose(t *testing.T) {
+	db := newTestDB(t, "TestBulkLoaderClose", nil)
+	defer db.Close()
+
+	l, err := NewBulkLoader(db, nil, BulkLoaderOptions{MemoryLimit: 1})
+	ensure.Nil(t, err)
+	ensure.Nil(t, l.Add([]byte("key"), []byte("val")))
+	dir := l.dir
+	ensure.Nil(t, l.Close())
+
+	_, err = os.Stat(dir)
+	ensure.True(t, os.IsNotExist(err))
+	ensure.DeepEqual(t, l.Finish(), errBulkLoaderClosed)
+}
+
+func TestBulkLoaderComparator(t *testing.T) {
+	db := newTestDB(t, "TestBulkLoaderComparator", func(opts *Options) {
+		opts.SetComparator(&bytesReverseComparator{})
+	})
+	defer db.Close()
+
+	// the comparator and options default to those of the database
+	l, err := NewBulkLoader(db, nil, BulkLoaderOptions{MemoryLimit: 16})
+	ensure.Nil(t, err)
+	for _, k := range []string{"b", "a", "c"} {
+		ensure.Nil(t, l.Add([]byte(k), []byte("val")))
+	}
+	ensure.Nil(t, l.Finish())
+
+	ro := NewDefaultReadOptions()
+	it := db.NewIterator(ro)
+	defer it.Close()
+	var keys []string
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		keys = append(keys, string(it.Key().Data()))
+	}
+	ensure.Nil(t, it.Err())
+	ensure.DeepEqual(t, keys, []string{"c", "b", "a"})
+}
+
+func TestBulkLoaderCleanupOnError(t *testing.T) {
+	db := newTestDB(t, "TestBulkLoaderCleanupOnError", nil)
+	defer db.Close()
+
+	tempDir, err := ioutil.TempDir("", "gorocksdb-TestBulkLoaderCleanupOnError")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(tempDir)
+
+	// the comparator does not match the options, so writing the sst
+	// files fails
+	l, err := NewBulkLoader(db, nil, BulkLoaderOptions{
+		Comparator:  &bytesReverseComparator{},
+		TempDir:     tempDir,
+		MemoryLimit: 16,
+	})
+	ensure.Nil(t, err)
+	for _, k := range []string{"b", "a", "c"} {
+		ensure.Nil(t, l.Add([]byte(k), []byte("val")))
+	}
+	ensure.NotNil(t, l.Finish())
+
+	files, err := ioutil.ReadDir(tempDir)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(files), 0)
+	ensure.DeepEqual(t, l.Finish(), errBulkLoaderClosed)
+
+	ro := NewDefaultReadOptions()
+	it := db.NewIterator(ro)
+	defer it.Close()
+	it.SeekToFirst()
+	ensure.False(t, it.Valid())
+}
+
+func TestMergeBulkLoaderRuns(t *testing.T) {
+	runs := []bulkLoaderRun{
+		&bulkLoaderMemRun{entries: []bulkLoaderEntry{{[]byte("a"), []byte("1")}, {[]byte("c"), []byte("1")}}},
+		&bulkLoaderMemRun{entries: []bulkLoaderEntry{{[]byte("b"), []byte("2")}, {[]byte("c"), []byte("2")}}},
+		&bulkLoaderMemRun{entries: []bulkLoaderEntry{{[]byte(""), []byte("3")}, {[]byte("a"), []byte("3")}}},
+	}
+	var merged []string
+	err := mergeBulkLoaderRuns(runs, bytes.Compare, func(e bulkLoaderEntry) error {
+		merged = append(merged, string(e.key)+"="+string(e.value))
+		return nil
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, merged, []string{"=3", "a=3", "b=2", "c=2"})
+}
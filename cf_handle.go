@@ -8,11 +8,15 @@ import "unsafe"
 // ColumnFamilyHandle represents a handle to a ColumnFamily.
 type ColumnFamilyHandle struct {
 	c *C.rocksdb_column_family_handle_t
+
+	// opts are the options the column family was opened or created with,
+	// if known.
+	opts *Options
 }
 
 // NewNativeColumnFamilyHandle creates a ColumnFamilyHandle object.
 func NewNativeColumnFamilyHandle(c *C.rocksdb_column_family_handle_t) *ColumnFamilyHandle {
-	return &ColumnFamilyHandle{c}
+	return &ColumnFamilyHandle{c: c}
 }
 
 // UnsafeGetCFHandler returns the underlying c column family handle.
@@ -0,0 +1,109 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"time"
+	"unsafe"
+)
+
+// ColumnFamilyMetadata describes the SST files of a column family.
+type ColumnFamilyMetadata struct {
+	Name      string
+	Size      int64
+	FileCount int
+
+	// Levels contains all levels of the column family, including empty
+	// ones, ordered from level 0.
+	Levels []LevelMetadata
+}
+
+// LevelMetadata describes the SST files of a level.
+type LevelMetadata struct {
+	Level int
+	Size  int64
+	Files []SstFileMetadata
+}
+
+// SstFileMetadata describes a single SST file.
+type SstFileMetadata struct {
+	// Name is the file name relative to the database directory, as
+	// accepted by CompactFiles.
+	Name          string
+	Size          int64
+	SmallestKey   []byte
+	LargestKey    []byte
+	SmallestSeqno uint64
+	LargestSeqno  uint64
+	NumEntries    uint64
+	NumDeletions  uint64
+
+	// BeingCompacted is true if the file is the input of a running
+	// compaction.
+	BeingCompacted bool
+
+	// FileCreationTime is the zero time if unknown.
+	FileCreationTime time.Time
+}
+
+// GetColumnFamilyMetaData returns the metadata of the default column family.
+func (db *DB) GetColumnFamilyMetaData() ColumnFamilyMetadata {
+	cMeta := C.gorocksdb_get_column_family_metadata(db.c, nil)
+	defer C.gorocksdb_column_family_metadata_destroy(cMeta)
+	return newColumnFamilyMetadata(cMeta)
+}
+
+// GetColumnFamilyMetaDataCF returns the metadata of the column family.
+func (db *DB) GetColumnFamilyMetaDataCF(cf *ColumnFamilyHandle) ColumnFamilyMetadata {
+	cMeta := C.gorocksdb_get_column_family_metadata(db.c, cf.c)
+	defer C.gorocksdb_column_family_metadata_destroy(cMeta)
+	return newColumnFamilyMetadata(cMeta)
+}
+
+func newColumnFamilyMetadata(cMeta *C.gorocksdb_column_family_metadata_t) ColumnFamilyMetadata {
+	meta := ColumnFamilyMetadata{
+		Name:      C.GoString(C.gorocksdb_column_family_metadata_name(cMeta)),
+		Size:      int64(C.gorocksdb_column_family_metadata_size(cMeta)),
+		FileCount: int(C.gorocksdb_column_family_metadata_file_count(cMeta)),
+		Levels:    make([]LevelMetadata, int(C.gorocksdb_column_family_metadata_level_count(cMeta))),
+	}
+	for i := range meta.Levels {
+		var (
+			cLevel     C.int
+			cSize      C.uint64_t
+			cFileCount C.size_t
+		)
+		C.gorocksdb_column_family_metadata_level(cMeta, C.size_t(i), &cLevel, &cSize, &cFileCount)
+		level := LevelMetadata{
+			Level: int(cLevel),
+			Size:  int64(cSize),
+			Files: make([]SstFileMetadata, int(cFileCount)),
+		}
+		for j := range level.Files {
+			var cFile C.gorocksdb_sstfilemetadata_t
+			C.gorocksdb_column_family_metadata_file(cMeta, C.size_t(i), C.size_t(j), &cFile)
+			level.Files[j] = newSstFileMetadata(&cFile)
+		}
+		meta.Levels[i] = level
+	}
+	return meta
+}
+
+// newSstFileMetadata converts the C metadata and releases its strings.
+func newSstFileMetadata(c *C.gorocksdb_sstfilemetadata_t) SstFileMetadata {
+	defer C.gorocksdb_sstfilemetadata_destroy(c)
+	return SstFileMetadata{
+		Name:             C.GoString(c.name),
+		Size:             int64(c.size),
+		SmallestKey:      C.GoBytes(unsafe.Pointer(c.smallest_key), C.int(c.smallest_key_len)),
+		LargestKey:       C.GoBytes(unsafe.Pointer(c.largest_key), C.int(c.largest_key_len)),
+		SmallestSeqno:    uint64(c.smallest_seqno),
+		LargestSeqno:     uint64(c.largest_seqno),
+		NumEntries:       uint64(c.num_entries),
+		NumDeletions:     uint64(c.num_deletions),
+		BeingCompacted:   c.being_compacted != 0,
+		FileCreationTime: unixTime(uint64(c.file_creation_time)),
+	}
+}
@@ -5,7 +5,7 @@ package gorocksdb
 import "C"
 
 import (
-	"errors"
+	"context"
 	"unsafe"
 )
 
@@ -44,11 +44,22 @@ func (checkpoint *Checkpoint) CreateCheckpoint(checkpoint_dir string, log_size_f
 	C.rocksdb_checkpoint_create(checkpoint.c, cDir, C.uint64_t(log_size_for_flush), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
 
+// CreateCheckpointContext builds a checkpoint like CreateCheckpoint unless
+// ctx is already done, in which case ctx.Err() is returned. ctx is only
+// checked before the checkpoint is created; creating a checkpoint cannot be
+// canceled once it started.
+func (checkpoint *Checkpoint) CreateCheckpointContext(ctx context.Context, checkpoint_dir string, log_size_for_flush uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return checkpoint.CreateCheckpoint(checkpoint_dir, log_size_for_flush)
+}
+
 // Destroy deallocates the Checkpoint object.
 func (checkpoint *Checkpoint) Destroy() {
 	C.rocksdb_checkpoint_object_destroy(checkpoint.c)
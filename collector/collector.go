@@ -0,0 +1,288 @@
+// Package collector periodically samples the properties, cache usage, memory
+// usage and statistics of a gorocksdb database and hands them to an
+// Exporter, e.g. the TextExporter which serves them in the Prometheus text
+// exposition format.
+//
+//	exporter := collector.NewTextExporter()
+//	c := collector.NewCollector(db, exporter)
+//	c.AddColumnFamily("default", cfDefault)
+//	c.AddCache("block", blockCache)
+//	c.Start(15 * time.Second)
+//	defer c.Stop()
+//	http.Handle("/metrics", exporter)
+package collector
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tecbot/gorocksdb"
+)
+
+// MetricType is the type of a sampled metric.
+type MetricType int
+
+// Metric types.
+const (
+	// Gauge is a value that can go up and down.
+	Gauge = MetricType(0)
+	// Counter is a value that only increases, except on restarts.
+	Counter = MetricType(1)
+)
+
+// Sample is a single sampled value.
+type Sample struct {
+	Name   string
+	Help   string
+	Type   MetricType
+	Labels map[string]string
+	Value  float64
+}
+
+// Exporter receives the samples of each collection.
+type Exporter interface {
+	// Export is called with all the samples of a collection. It must not
+	// retain the slice after returning.
+	Export(samples []Sample) error
+}
+
+// Property is a RocksDB integer property sampled by the Collector.
+type Property struct {
+	// Name is the RocksDB property name, e.g. "rocksdb.estimate-num-keys".
+	Name string
+	// Help describes the property.
+	Help string
+}
+
+// DefaultProperties is the curated set of integer properties sampled by a
+// new Collector.
+var DefaultProperties = []Property{
+	{"rocksdb.estimate-num-keys", "Estimated number of keys."},
+	{"rocksdb.estimate-live-data-size", "Estimated size of the live data in bytes."},
+	{"rocksdb.total-sst-files-size", "Total size of all SST files in bytes."},
+	{"rocksdb.live-sst-files-size", "Total size of the SST files of the current version in bytes."},
+	{"rocksdb.cur-size-all-mem-tables", "Approximate size of the active and unflushed immutable memtables in bytes."},
+	{"rocksdb.size-all-mem-tables", "Approximate size of all memtables in bytes."},
+	{"rocksdb.num-entries-active-mem-table", "Number of entries in the active memtable."},
+	{"rocksdb.num-deletes-active-mem-table", "Number of deletes in the active memtable."},
+	{"rocksdb.num-immutable-mem-table", "Number of immutable memtables that have not yet been flushed."},
+	{"rocksdb.mem-table-flush-pending", "1 if a memtable flush is pending, otherwise 0."},
+	{"rocksdb.num-running-flushes", "Number of currently running flushes."},
+	{"rocksdb.compaction-pending", "1 if at least one compaction is pending, otherwise 0."},
+	{"rocksdb.num-running-compactions", "Number of currently running compactions."},
+	{"rocksdb.estimate-pending-compaction-bytes", "Estimated number of bytes compaction needs to rewrite."},
+	{"rocksdb.background-errors", "Accumulated number of background errors."},
+	{"rocksdb.num-snapshots", "Number of unreleased snapshots."},
+	{"rocksdb.num-live-versions", "Number of live versions."},
+	{"rocksdb.estimate-table-readers-mem", "Estimated memory used by table readers in bytes, not including the block cache."},
+	{"rocksdb.actual-delayed-write-rate", "Current delayed write rate in bytes per second, 0 if writes are not delayed."},
+	{"rocksdb.is-write-stopped", "1 if writes are stopped, otherwise 0."},
+}
+
+// Collector samples a database and exports the samples.
+type Collector struct {
+	db       *gorocksdb.DB
+	exporter Exporter
+
+	mu         sync.Mutex
+	properties []Property
+	cfNames    []string
+	cfs        []*gorocksdb.ColumnFamilyHandle
+	cacheNames []string
+	caches     []*gorocksdb.Cache
+	stats      *gorocksdb.Statistics
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewCollector creates a Collector for the database which samples
+// DefaultProperties of the default column family.
+func NewCollector(db *gorocksdb.DB, exporter Exporter) *Collector {
+	return &Collector{
+		db:         db,
+		exporter:   exporter,
+		properties: DefaultProperties,
+	}
+}
+
+// SetProperties replaces the sampled integer properties.
+func (c *Collector) SetProperties(properties []Property) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.properties = properties
+}
+
+// AddColumnFamily samples the properties of the column family, labeled with
+// cf="name". Once a column family is added, the properties are no longer
+// sampled through DB.GetProperty, so add the default column family as well
+// if it should be sampled.
+func (c *Collector) AddColumnFamily(name string, cf *gorocksdb.ColumnFamilyHandle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfNames = append(c.cfNames, name)
+	c.cfs = append(c.cfs, cf)
+}
+
+// AddCache samples the usage of the cache, labeled with cache="name". The
+// cache is also taken into account for the approximate memory usage.
+func (c *Collector) AddCache(name string, cache *gorocksdb.Cache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cacheNames = append(c.cacheNames, name)
+	c.caches = append(c.caches, cache)
+}
+
+// EnableStatistics samples the statistics tickers of the database. The
+// database must have been opened with Options.EnableStatistics.
+func (c *Collector) EnableStatistics() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = c.db.GetStatistics()
+}
+
+// Collect samples the database once and returns the samples.
+func (c *Collector) Collect() []Sample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var samples []Sample
+	for _, p := range c.properties {
+		name := metricName(p.Name)
+		if len(c.cfs) == 0 {
+			if v, ok := parseProperty(c.db.GetProperty(p.Name)); ok {
+				samples = append(samples, Sample{
+					Name: name, Help: p.Help, Type: Gauge, Value: v,
+					Labels: map[string]string{"cf": "default"},
+				})
+			}
+			continue
+		}
+		for i, cf := range c.cfs {
+			if v, ok := parseProperty(c.db.GetPropertyCF(p.Name, cf)); ok {
+				samples = append(samples, Sample{
+					Name: name, Help: p.Help, Type: Gauge, Value: v,
+					Labels: map[string]string{"cf": c.cfNames[i]},
+				})
+			}
+		}
+	}
+
+	for i, cache := range c.caches {
+		labels := map[string]string{"cache": c.cacheNames[i]}
+		samples = append(samples,
+			Sample{
+				Name: "rocksdb_cache_usage_bytes", Help: "Memory size of the entries residing in the cache.",
+				Type: Gauge, Labels: labels, Value: float64(cache.GetUsage()),
+			},
+			Sample{
+				Name: "rocksdb_cache_pinned_usage_bytes", Help: "Memory size of the entries pinned in the cache.",
+				Type: Gauge, Labels: labels, Value: float64(cache.GetPinnedUsage()),
+			},
+		)
+	}
+
+	if usage, err := gorocksdb.GetApproximateMemoryUsageByType([]*gorocksdb.DB{c.db}, c.caches); err == nil {
+		samples = append(samples,
+			Sample{
+				Name: "rocksdb_memory_mem_table_total_bytes", Help: "Approximate memory usage of all memtables.",
+				Type: Gauge, Value: float64(usage.MemTableTotal),
+			},
+			Sample{
+				Name: "rocksdb_memory_mem_table_unflushed_bytes", Help: "Approximate memory usage of unflushed memtables.",
+				Type: Gauge, Value: float64(usage.MemTableUnflushed),
+			},
+			Sample{
+				Name: "rocksdb_memory_table_readers_total_bytes", Help: "Approximate memory usage of table readers.",
+				Type: Gauge, Value: float64(usage.MemTableReadersTotal),
+			},
+			Sample{
+				Name: "rocksdb_memory_cache_total_bytes", Help: "Approximate memory usage of the caches.",
+				Type: Gauge, Value: float64(usage.CacheTotal),
+			},
+		)
+	}
+
+	if c.stats != nil {
+		tickers, _ := c.stats.Snapshot()
+		names := make(map[string]gorocksdb.Ticker, len(tickers))
+		sorted := make([]string, 0, len(tickers))
+		for t := range tickers {
+			names[t.String()] = t
+			sorted = append(sorted, t.String())
+		}
+		sort.Strings(sorted)
+		for _, n := range sorted {
+			samples = append(samples, Sample{
+				Name:  metricName(n) + "_total",
+				Help:  "RocksDB statistics ticker " + n + ".",
+				Type:  Counter,
+				Value: float64(tickers[names[n]]),
+			})
+		}
+	}
+
+	return samples
+}
+
+// CollectAndExport samples the database once and exports the samples.
+func (c *Collector) CollectAndExport() error {
+	return c.exporter.Export(c.Collect())
+}
+
+// Start samples and exports the database every interval until Stop is
+// called. Export errors are ignored; the next collection is attempted
+// anyway.
+func (c *Collector) Start(interval time.Duration) {
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		c.CollectAndExport()
+		for {
+			select {
+			case <-ticker.C:
+				c.CollectAndExport()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the collection started by Start and waits until a running
+// collection has finished. It must be called before the database is closed.
+func (c *Collector) Stop() {
+	if c.stop == nil {
+		return
+	}
+	close(c.stop)
+	<-c.done
+	c.stop = nil
+}
+
+// metricName converts a RocksDB property or ticker name to a metric name,
+// e.g. "rocksdb.estimate-num-keys" to "rocksdb_estimate_num_keys".
+func metricName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func parseProperty(value string) (float64, bool) {
+	v, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return float64(v), true
+}
@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/tecbot/gorocksdb"
+)
+
+func TestCollector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestCollector")
+	ensure.Nil(t, err)
+
+	cache := gorocksdb.NewLRUCache(1 << 20)
+	defer cache.Destroy()
+	bbto := gorocksdb.NewDefaultBlockBasedTableOptions()
+	bbto.SetBlockCache(cache)
+	opts := gorocksdb.NewDefaultOptions()
+	opts.SetBlockBasedTableFactory(bbto)
+	opts.SetCreateIfMissing(true)
+	opts.EnableStatistics()
+	db, err := gorocksdb.OpenDb(opts, dir)
+	ensure.Nil(t, err)
+	defer db.Close()
+
+	wo := gorocksdb.NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("hello"), []byte("world")))
+
+	exporter := NewTextExporter()
+	c := NewCollector(db, exporter)
+	c.AddCache("block", cache)
+	c.EnableStatistics()
+	ensure.Nil(t, c.CollectAndExport())
+
+	var buf bytes.Buffer
+	_, err = exporter.WriteTo(&buf)
+	ensure.Nil(t, err)
+	out := buf.String()
+	ensure.True(t, strings.Contains(out, `rocksdb_estimate_num_keys{cf="default"} 1`))
+	ensure.True(t, strings.Contains(out, `rocksdb_cache_usage_bytes{cache="block"} `))
+	ensure.True(t, strings.Contains(out, "rocksdb_memory_mem_table_total_bytes "))
+	ensure.True(t, strings.Contains(out, "rocksdb_number_keys_written_total 1\n"))
+
+	// periodic collection
+	c.Start(time.Millisecond)
+	c.Stop()
+}
@@ -0,0 +1,122 @@
+package collector
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TextExporter keeps the samples of the latest collection and writes them in
+// the Prometheus text exposition format. It implements http.Handler, so it
+// can be registered as the scrape endpoint.
+type TextExporter struct {
+	mu      sync.RWMutex
+	samples []Sample
+}
+
+// NewTextExporter creates a TextExporter.
+func NewTextExporter() *TextExporter {
+	return &TextExporter{}
+}
+
+// Export implements Exporter.
+func (e *TextExporter) Export(samples []Sample) error {
+	cp := make([]Sample, len(samples))
+	copy(cp, samples)
+	e.mu.Lock()
+	e.samples = cp
+	e.mu.Unlock()
+	return nil
+}
+
+// WriteTo writes the samples of the latest collection to w.
+func (e *TextExporter) WriteTo(w io.Writer) (int64, error) {
+	e.mu.RLock()
+	samples := e.samples
+	e.mu.RUnlock()
+
+	// group the samples by name, keeping the order of their first occurrence.
+	var names []string
+	byName := make(map[string][]Sample)
+	for _, s := range samples {
+		if _, ok := byName[s.Name]; !ok {
+			names = append(names, s.Name)
+		}
+		byName[s.Name] = append(byName[s.Name], s)
+	}
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	for _, name := range names {
+		group := byName[name]
+		if help := group[0].Help; help != "" {
+			cw.WriteString("# HELP " + name + " " + escapeHelp(help) + "\n")
+		}
+		typ := "gauge"
+		if group[0].Type == Counter {
+			typ = "counter"
+		}
+		cw.WriteString("# TYPE " + name + " " + typ + "\n")
+		for _, s := range group {
+			cw.WriteString(name)
+			writeLabels(cw, s.Labels)
+			cw.WriteString(" " + strconv.FormatFloat(s.Value, 'g', -1, 64) + "\n")
+		}
+	}
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP implements http.Handler.
+func (e *TextExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.WriteTo(w)
+}
+
+func writeLabels(cw *countingWriter, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	cw.WriteString("{")
+	for i, k := range keys {
+		if i > 0 {
+			cw.WriteString(",")
+		}
+		cw.WriteString(k + `="` + escapeLabelValue(labels[k]) + `"`)
+	}
+	cw.WriteString("}")
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string       { return helpEscaper.Replace(s) }
+func escapeLabelValue(s string) string { return labelValueEscaper.Replace(s) }
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) WriteString(s string) {
+	if cw.err != nil {
+		return
+	}
+	n, err := cw.w.WriteString(s)
+	cw.n += int64(n)
+	cw.err = err
+}
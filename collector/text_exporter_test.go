@@ -0,0 +1,34 @@
+package collector
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestTextExporter(t *testing.T) {
+	e := NewTextExporter()
+	ensure.Nil(t, e.Export([]Sample{
+		{Name: "rocksdb_estimate_num_keys", Help: "Estimated number of keys.", Type: Gauge, Labels: map[string]string{"cf": "default"}, Value: 3},
+		{Name: "rocksdb_estimate_num_keys", Help: "Estimated number of keys.", Type: Gauge, Labels: map[string]string{"cf": "say \"hi\""}, Value: 1.5},
+		{Name: "rocksdb_block_cache_miss_total", Type: Counter, Value: 42},
+	}))
+
+	var buf bytes.Buffer
+	n, err := e.WriteTo(&buf)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, int64(buf.Len()))
+	ensure.DeepEqual(t, buf.String(), `# HELP rocksdb_estimate_num_keys Estimated number of keys.
+# TYPE rocksdb_estimate_num_keys gauge
+rocksdb_estimate_num_keys{cf="default"} 3
+rocksdb_estimate_num_keys{cf="say \"hi\""} 1.5
+# TYPE rocksdb_block_cache_miss_total counter
+rocksdb_block_cache_miss_total 42
+`)
+}
+
+func TestMetricName(t *testing.T) {
+	ensure.DeepEqual(t, metricName("rocksdb.estimate-num-keys"), "rocksdb_estimate_num_keys")
+	ensure.DeepEqual(t, metricName("rocksdb.compaction.key.drop.range_del"), "rocksdb_compaction_key_drop_range_del")
+}
@@ -1,6 +1,7 @@
 package gorocksdb
 
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
 // A CompactionFilter can be used to filter keys during compaction time.
@@ -25,6 +26,52 @@ type CompactionFilter interface {
 	Name() string
 }
 
+// CompactionValueType specifies the type of the value passed to an
+// ExtendedCompactionFilter.
+type CompactionValueType int
+
+// Compaction value types.
+const (
+	// CompactionValue is a regular value written with Put.
+	CompactionValue = CompactionValueType(0)
+	// CompactionMergeOperand is an operand written with Merge.
+	CompactionMergeOperand = CompactionValueType(1)
+	// CompactionBlobIndex is a reference to a value stored in a blob file.
+	CompactionBlobIndex = CompactionValueType(2)
+)
+
+// CompactionDecision is returned by an ExtendedCompactionFilter to specify
+// what happens to a key-value during compaction.
+type CompactionDecision int
+
+// Compaction decisions.
+const (
+	// CompactionKeep preserves the key-value.
+	CompactionKeep = CompactionDecision(0)
+	// CompactionRemove removes the key-value.
+	CompactionRemove = CompactionDecision(1)
+	// CompactionChangeValue replaces the value with the returned new value.
+	CompactionChangeValue = CompactionDecision(2)
+	// CompactionRemoveAndSkipUntil removes the key-value and all following
+	// keys up to, but excluding, the returned skip-until key. The skipped
+	// keys are not passed to the filter. The skip-until key must be greater
+	// than the current key, otherwise the decision is treated as
+	// CompactionKeep.
+	CompactionRemoveAndSkipUntil = CompactionDecision(3)
+)
+
+// An ExtendedCompactionFilter is a CompactionFilter which is also called
+// for merge operands and blob indexes and can skip ranges of keys.
+// If a filter implements this interface, FilterV2 is called instead of Filter.
+type ExtendedCompactionFilter interface {
+	CompactionFilter
+
+	// FilterV2 decides what happens to the key-value. newVal is only used
+	// for CompactionChangeValue and skipUntil only for
+	// CompactionRemoveAndSkipUntil.
+	FilterV2(level int, key []byte, valueType CompactionValueType, val []byte) (decision CompactionDecision, newVal, skipUntil []byte)
+}
+
 // NewNativeCompactionFilter creates a CompactionFilter object.
 func NewNativeCompactionFilter(c *C.rocksdb_compactionfilter_t) CompactionFilter {
 	return nativeCompactionFilter{c}
@@ -71,3 +118,46 @@ func gorocksdb_compactionfilter_filter(idx int, cLevel C.int, cKey *C.char, cKey
 func gorocksdb_compactionfilter_name(idx int) *C.char {
 	return compactionFilters.Get(idx).(compactionFilterWrapper).name
 }
+
+//export gorocksdb_compactionfilter_filter_v2
+func gorocksdb_compactionfilter_filter_v2(idx int, cJob C.uchar, cLevel C.int, cKey *C.char, cKeyLen C.size_t, cValueType C.int, cVal *C.char, cValLen C.size_t, cNewVal **C.char, cNewValLen *C.size_t, cSkipUntil **C.char, cSkipUntilLen *C.size_t) C.int {
+	key := charToByte(cKey, cKeyLen)
+	val := charToByte(cVal, cValLen)
+
+	var filter CompactionFilter
+	if cJob != 0 {
+		v, _ := compactionJobFilters.Load(idx)
+		filter = v.(compactionFilterWrapper).filter
+	} else {
+		filter = compactionFilters.Get(idx).(compactionFilterWrapper).filter
+	}
+
+	decision, newVal, skipUntil := filterV2(filter, int(cLevel), key, CompactionValueType(cValueType), val)
+	switch decision {
+	case CompactionChangeValue:
+		*cNewVal = byteToChar(newVal)
+		*cNewValLen = C.size_t(len(newVal))
+	case CompactionRemoveAndSkipUntil:
+		*cSkipUntil = byteToChar(skipUntil)
+		*cSkipUntilLen = C.size_t(len(skipUntil))
+	}
+	return C.int(decision)
+}
+
+// filterV2 calls FilterV2 for an ExtendedCompactionFilter and otherwise
+// maps the result of Filter to a decision. Plain filters only see values.
+func filterV2(filter CompactionFilter, level int, key []byte, valueType CompactionValueType, val []byte) (CompactionDecision, []byte, []byte) {
+	if ef, ok := filter.(ExtendedCompactionFilter); ok {
+		return ef.FilterV2(level, key, valueType, val)
+	}
+	if valueType != CompactionValue {
+		return CompactionKeep, nil, nil
+	}
+	remove, newVal := filter.Filter(level, key, val)
+	if remove {
+		return CompactionRemove, nil, nil
+	} else if newVal != nil {
+		return CompactionChangeValue, newVal, nil
+	}
+	return CompactionKeep, nil, nil
+}
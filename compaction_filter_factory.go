@@ -0,0 +1,106 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// CompactionFilterContext describes the compaction job a CompactionFilter
+// is created for.
+type CompactionFilterContext struct {
+	// IsFullCompaction is true if the compaction includes all files of
+	// the column family.
+	IsFullCompaction bool
+
+	// IsManualCompaction is true if the compaction was requested by the
+	// application, e.g. through CompactRange.
+	IsManualCompaction bool
+
+	// ColumnFamilyID is the id of the column family being compacted.
+	ColumnFamilyID uint32
+}
+
+// A CompactionFilterFactory creates a new CompactionFilter for each
+// compaction run. Each created filter is only used from a single thread
+// and so does not need to be thread-safe.
+type CompactionFilterFactory interface {
+	// CreateCompactionFilter returns the filter for a single compaction job.
+	// Returning nil disables filtering for that job.
+	CreateCompactionFilter(context CompactionFilterContext) CompactionFilter
+
+	// The name of the compaction filter factory, for logging
+	Name() string
+}
+
+// A CompactionFilterDestroyer is a CompactionFilter created by a
+// CompactionFilterFactory which wants to release per-job state.
+// Destroy is called once the compaction job it was created for is finished.
+type CompactionFilterDestroyer interface {
+	Destroy()
+}
+
+// Hold references to compaction filter factories.
+var compactionFilterFactories = NewCOWList()
+
+type compactionFilterFactoryWrapper struct {
+	name    *C.char
+	factory CompactionFilterFactory
+}
+
+func registerCompactionFilterFactory(factory CompactionFilterFactory) int {
+	return compactionFilterFactories.Append(compactionFilterFactoryWrapper{C.CString(factory.Name()), factory})
+}
+
+// Hold references to the compaction filters of running compaction jobs.
+// Unlike the other registries, entries are removed again once the job
+// is finished, so a map is used instead of a COWList.
+var (
+	compactionJobFilters   sync.Map
+	compactionJobFilterSeq int64
+)
+
+//export gorocksdb_compactionfilterfactory_create_filter
+func gorocksdb_compactionfilterfactory_create_filter(idx int, cIsFull C.uchar, cIsManual C.uchar, cCFID C.uint32_t) int {
+	filter := compactionFilterFactories.Get(idx).(compactionFilterFactoryWrapper).factory.CreateCompactionFilter(CompactionFilterContext{
+		IsFullCompaction:   cIsFull != 0,
+		IsManualCompaction: cIsManual != 0,
+		ColumnFamilyID:     uint32(cCFID),
+	})
+	if filter == nil {
+		return 0
+	}
+	// 0 is reserved for "no filter"
+	filterIdx := int(atomic.AddInt64(&compactionJobFilterSeq, 1))
+	compactionJobFilters.Store(filterIdx, compactionFilterWrapper{C.CString(filter.Name()), filter})
+	return filterIdx
+}
+
+//export gorocksdb_compactionfilterfactory_destroy_filter
+func gorocksdb_compactionfilterfactory_destroy_filter(idx int) {
+	v, ok := compactionJobFilters.Load(idx)
+	if !ok {
+		return
+	}
+	compactionJobFilters.Delete(idx)
+	wrapper := v.(compactionFilterWrapper)
+	C.free(unsafe.Pointer(wrapper.name))
+	if d, ok := wrapper.filter.(CompactionFilterDestroyer); ok {
+		d.Destroy()
+	}
+}
+
+//export gorocksdb_compactionfilterfactory_filter_name
+func gorocksdb_compactionfilterfactory_filter_name(idx int) *C.char {
+	v, _ := compactionJobFilters.Load(idx)
+	return v.(compactionFilterWrapper).name
+}
+
+//export gorocksdb_compactionfilterfactory_name
+func gorocksdb_compactionfilterfactory_name(idx int) *C.char {
+	return compactionFilterFactories.Get(idx).(compactionFilterFactoryWrapper).name
+}
@@ -2,6 +2,8 @@ package gorocksdb
 
 import (
 	"bytes"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/facebookgo/ensure"
@@ -59,3 +61,132 @@ func (m *mockCompactionFilter) Name() string { return "gorocksdb.test" }
 func (m *mockCompactionFilter) Filter(level int, key, val []byte) (bool, []byte) {
 	return m.filter(level, key, val)
 }
+
+func TestCompactionFilterFactory(t *testing.T) {
+	// filters are created and destroyed by the compaction threads
+	var (
+		mu        sync.Mutex
+		contexts  []CompactionFilterContext
+		destroyed int
+	)
+	factory := &mockCompactionFilterFactory{
+		create: func(context CompactionFilterContext) CompactionFilter {
+			mu.Lock()
+			defer mu.Unlock()
+			contexts = append(contexts, context)
+			return &mockDestroyableCompactionFilter{
+				mockCompactionFilter: mockCompactionFilter{
+					filter: func(level int, key, val []byte) (bool, []byte) {
+						return bytes.Equal(key, []byte("delete")), nil
+					},
+				},
+				destroy: func() {
+					mu.Lock()
+					defer mu.Unlock()
+					destroyed++
+				},
+			}
+		},
+	}
+	db := newTestDB(t, "TestCompactionFilterFactory", func(opts *Options) {
+		opts.SetCompactionFilterFactory(factory)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("keep"), []byte("val")))
+	ensure.Nil(t, db.Put(wo, []byte("delete"), []byte("val")))
+
+	// trigger a manual compaction
+	db.CompactRange(Range{nil, nil})
+
+	mu.Lock()
+	ensure.DeepEqual(t, len(contexts), 1)
+	ensure.True(t, contexts[0].IsManualCompaction)
+	ensure.DeepEqual(t, contexts[0].ColumnFamilyID, uint32(0))
+	ensure.DeepEqual(t, destroyed, 1)
+	mu.Unlock()
+
+	ro := NewDefaultReadOptions()
+	v1, err := db.Get(ro, []byte("keep"))
+	defer v1.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1.Data(), []byte("val"))
+
+	v2, err := db.Get(ro, []byte("delete"))
+	ensure.Nil(t, err)
+	ensure.True(t, v2.Data() == nil)
+}
+
+type mockCompactionFilterFactory struct {
+	create func(context CompactionFilterContext) CompactionFilter
+}
+
+func (m *mockCompactionFilterFactory) Name() string { return "gorocksdb.test" }
+func (m *mockCompactionFilterFactory) CreateCompactionFilter(context CompactionFilterContext) CompactionFilter {
+	return m.create(context)
+}
+
+type mockDestroyableCompactionFilter struct {
+	mockCompactionFilter
+	destroy func()
+}
+
+func (m *mockDestroyableCompactionFilter) Destroy() { m.destroy() }
+
+func TestExtendedCompactionFilter(t *testing.T) {
+	// counted by the compaction threads
+	var operands int64
+	db := newTestDB(t, "TestExtendedCompactionFilter", func(opts *Options) {
+		opts.SetMergeOperator(&mockMergeOperator{
+			fullMerge: func(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+				return append(existingValue, bytes.Join(operands, nil)...), true
+			},
+		})
+		opts.SetCompactionFilter(&mockExtendedCompactionFilter{
+			filter: func(level int, key []byte, valueType CompactionValueType, val []byte) (CompactionDecision, []byte, []byte) {
+				switch {
+				case bytes.HasPrefix(key, []byte("tenant1/")):
+					// drop the whole tenant
+					return CompactionRemoveAndSkipUntil, nil, []byte("tenant10")
+				case valueType == CompactionMergeOperand:
+					atomic.AddInt64(&operands, 1)
+					return CompactionChangeValue, bytes.ToUpper(val), nil
+				}
+				return CompactionKeep, nil, nil
+			},
+		})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("tenant1/a"), []byte("a")))
+	ensure.Nil(t, db.Put(wo, []byte("tenant1/b"), []byte("b")))
+	ensure.Nil(t, db.Put(wo, []byte("tenant2/a"), []byte("a")))
+
+	// make sure the operand is not merged before the compaction filter sees it
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	ensure.Nil(t, db.Merge(wo, []byte("tenant2/a"), []byte("b")))
+	db.CompactRange(Range{nil, nil})
+	ensure.True(t, atomic.LoadInt64(&operands) > 0)
+
+	ro := NewDefaultReadOptions()
+	for _, key := range []string{"tenant1/a", "tenant1/b"} {
+		v, err := db.Get(ro, []byte(key))
+		ensure.Nil(t, err)
+		ensure.True(t, v.Data() == nil)
+	}
+	v, err := db.Get(ro, []byte("tenant2/a"))
+	defer v.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("aB"))
+}
+
+type mockExtendedCompactionFilter struct {
+	mockCompactionFilter
+	filter func(level int, key []byte, valueType CompactionValueType, val []byte) (CompactionDecision, []byte, []byte)
+}
+
+func (m *mockExtendedCompactionFilter) FilterV2(level int, key []byte, valueType CompactionValueType, val []byte) (CompactionDecision, []byte, []byte) {
+	return m.filter(level, key, valueType, val)
+}
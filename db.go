@@ -2,10 +2,13 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 	"unsafe"
 )
 
@@ -33,7 +36,7 @@ func OpenDb(opts *Options, name string) (*DB, error) {
 	db := C.rocksdb_open(opts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return &DB{
 		name: name,
@@ -52,7 +55,7 @@ func OpenDbWithTTL(opts *Options, name string, ttl int) (*DB, error) {
 	db := C.rocksdb_open_with_ttl(opts.c, cName, C.int(ttl), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return &DB{
 		name: name,
@@ -71,7 +74,7 @@ func OpenDbForReadOnly(opts *Options, name string, errorIfLogFileExist bool) (*D
 	db := C.rocksdb_open_for_read_only(opts.c, cName, boolToChar(errorIfLogFileExist), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return &DB{
 		name: name,
@@ -124,12 +127,12 @@ func OpenDbColumnFamilies(
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, nil, errors.New(C.GoString(cErr))
+		return nil, nil, newError(C.GoString(cErr))
 	}
 
 	cfHandles := make([]*ColumnFamilyHandle, numColumnFamilies)
 	for i, c := range cHandles {
-		cfHandles[i] = NewNativeColumnFamilyHandle(c)
+		cfHandles[i] = &ColumnFamilyHandle{c: c, opts: cfOpts[i]}
 	}
 
 	return &DB{
@@ -186,12 +189,12 @@ func OpenDbForReadOnlyColumnFamilies(
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, nil, errors.New(C.GoString(cErr))
+		return nil, nil, newError(C.GoString(cErr))
 	}
 
 	cfHandles := make([]*ColumnFamilyHandle, numColumnFamilies)
 	for i, c := range cHandles {
-		cfHandles[i] = NewNativeColumnFamilyHandle(c)
+		cfHandles[i] = &ColumnFamilyHandle{c: c, opts: cfOpts[i]}
 	}
 
 	return &DB{
@@ -212,7 +215,7 @@ func ListColumnFamilies(opts *Options, name string) ([]string, error) {
 	cNames := C.rocksdb_list_column_families(opts.c, cName, &cLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	namesLen := int(cLen)
 	names := make([]string, namesLen)
@@ -247,7 +250,7 @@ func (db *DB) Get(opts *ReadOptions, key []byte) (*Slice, error) {
 	cValue := C.rocksdb_get(db.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return NewSlice(cValue, cValLen), nil
 }
@@ -262,7 +265,7 @@ func (db *DB) GetBytes(opts *ReadOptions, key []byte) ([]byte, error) {
 	cValue := C.rocksdb_get(db.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	if cValue == nil {
 		return nil, nil
@@ -281,7 +284,7 @@ func (db *DB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Sli
 	cValue := C.rocksdb_get_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return NewSlice(cValue, cValLen), nil
 }
@@ -295,102 +298,181 @@ func (db *DB) GetPinned(opts *ReadOptions, key []byte) (*PinnableSliceHandle, er
 	cHandle := C.rocksdb_get_pinned(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return NewNativePinnableSliceHandle(cHandle), nil
 }
 
 // MultiGet returns the data associated with the passed keys from the database
 func (db *DB) MultiGet(opts *ReadOptions, keys ...[]byte) (Slices, error) {
+	return db.multiGet(opts, nil, keys)
+}
+
+// MultiGetCF returns the data associated with the passed keys from the column family
+func (db *DB) MultiGetCF(opts *ReadOptions, cf *ColumnFamilyHandle, keys ...[]byte) (Slices, error) {
+	cfs := make(ColumnFamilyHandles, len(keys))
+	for i := 0; i < len(keys); i++ {
+		cfs[i] = cf
+	}
+	return db.MultiGetCFMultiCF(opts, cfs, keys)
+}
+
+// MultiGetCFMultiCF returns the data associated with the passed keys and
+// column families.
+func (db *DB) MultiGetCFMultiCF(opts *ReadOptions, cfs ColumnFamilyHandles, keys [][]byte) (Slices, error) {
+	return db.multiGet(opts, cfs.toCSlice(), keys)
+}
+
+// multiGet reads the keys from the column families, or from the default
+// column family if cCfs is nil.
+func (db *DB) multiGet(opts *ReadOptions, cCfs columnFamilySlice, keys [][]byte) (Slices, error) {
+	if len(keys) == 0 {
+		return Slices{}, nil
+	}
 	cKeys, cKeySizes := byteSlicesToCSlices(keys)
 	defer cKeys.Destroy()
 	vals := make(charsSlice, len(keys))
 	valSizes := make(sizeTSlice, len(keys))
-	rocksErrs := make(charsSlice, len(keys))
+	statuses := make([]C.gorocksdb_status_t, len(keys))
 
-	C.rocksdb_multi_get(
+	C.gorocksdb_multi_get(
 		db.c,
 		opts.c,
+		cCfs.c(),
 		C.size_t(len(keys)),
 		cKeys.c(),
 		cKeySizes.c(),
 		vals.c(),
 		valSizes.c(),
-		rocksErrs.c(),
+		&statuses[0],
 	)
 
 	var errs []error
 
-	for i, rocksErr := range rocksErrs {
-		if rocksErr != nil {
-			defer C.rocksdb_free(unsafe.Pointer(rocksErr))
-			err := fmt.Errorf("getting %q failed: %v", string(keys[i]), C.GoString(rocksErr))
-			errs = append(errs, err)
+	for i := range statuses {
+		if err := newStatusError(&statuses[i]); err != nil {
+			errs = append(errs, fmt.Errorf("getting %q failed: %w", string(keys[i]), err))
 		}
 	}
 
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("failed to get %d keys, first error: %v", len(errs), errs[0])
-	}
-
 	slices := make(Slices, len(keys))
 	for i, val := range vals {
 		slices[i] = NewSlice(val, valSizes[i])
 	}
 
+	if len(errs) > 0 {
+		slices.Destroy()
+		return nil, fmt.Errorf("failed to get %d keys, first error: %w", len(errs), errs[0])
+	}
+
 	return slices, nil
 }
 
-// MultiGetCF returns the data associated with the passed keys from the column family
-func (db *DB) MultiGetCF(opts *ReadOptions, cf *ColumnFamilyHandle, keys ...[]byte) (Slices, error) {
-	cfs := make(ColumnFamilyHandles, len(keys))
-	for i := 0; i < len(keys); i++ {
-		cfs[i] = cf
+// KeyMayExist returns false if the key definitely does not exist in the
+// database. It only checks data which is in memory, like the memtables,
+// bloom filters and cached blocks, so it is cheaper than Get but may
+// return true for keys which do not exist.
+func (db *DB) KeyMayExist(opts *ReadOptions, key []byte) bool {
+	cKey := byteToChar(key)
+	return C.rocksdb_key_may_exist(db.c, opts.c, cKey, C.size_t(len(key)), nil, nil, nil, 0, nil) != 0
+}
+
+// KeyMayExistCF is like KeyMayExist for the column family.
+func (db *DB) KeyMayExistCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) bool {
+	cKey := byteToChar(key)
+	return C.rocksdb_key_may_exist_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), nil, nil, nil, 0, nil) != 0
+}
+
+// KeyMayExistWithValue is like KeyMayExist, but also returns the value if
+// it was found without reading from storage, e.g. in the memtable.
+// Otherwise the returned slice is nil.
+func (db *DB) KeyMayExistWithValue(opts *ReadOptions, key []byte) (bool, *Slice) {
+	var (
+		cValue  *C.char
+		cValLen C.size_t
+		cFound  C.uchar
+		cKey    = byteToChar(key)
+	)
+	mayExist := C.rocksdb_key_may_exist(db.c, opts.c, cKey, C.size_t(len(key)), &cValue, &cValLen, nil, 0, &cFound) != 0
+	if cFound == 0 {
+		return mayExist, nil
 	}
-	return db.MultiGetCFMultiCF(opts, cfs, keys)
+	return mayExist, NewSlice(cValue, cValLen)
 }
 
-// MultiGetCFMultiCF returns the data associated with the passed keys and
-// column families.
-func (db *DB) MultiGetCFMultiCF(opts *ReadOptions, cfs ColumnFamilyHandles, keys [][]byte) (Slices, error) {
+// KeyMayExistWithValueCF is like KeyMayExistWithValue for the column family.
+func (db *DB) KeyMayExistWithValueCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (bool, *Slice) {
+	var (
+		cValue  *C.char
+		cValLen C.size_t
+		cFound  C.uchar
+		cKey    = byteToChar(key)
+	)
+	mayExist := C.rocksdb_key_may_exist_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cValue, &cValLen, nil, 0, &cFound) != 0
+	if cFound == 0 {
+		return mayExist, nil
+	}
+	return mayExist, NewSlice(cValue, cValLen)
+}
+
+// BatchedMultiGet returns the data associated with the passed keys from the
+// database. Unlike MultiGet, the keys are looked up in a single batch and
+// the values are pinned instead of copied. If sortedInput is true, the keys
+// must be sorted by the comparator of the database, which saves sorting
+// them. The handle of a key which does not exist has no data.
+func (db *DB) BatchedMultiGet(opts *ReadOptions, sortedInput bool, keys ...[]byte) (PinnableSliceHandles, error) {
+	cCf := C.rocksdb_get_default_column_family_handle(db.c)
+	defer C.rocksdb_column_family_handle_destroy(cCf)
+	return db.batchedMultiGet(opts, cCf, sortedInput, keys)
+}
+
+// BatchedMultiGetCF is like BatchedMultiGet for the column family.
+func (db *DB) BatchedMultiGetCF(opts *ReadOptions, cf *ColumnFamilyHandle, sortedInput bool, keys ...[]byte) (PinnableSliceHandles, error) {
+	return db.batchedMultiGet(opts, cf.c, sortedInput, keys)
+}
+
+func (db *DB) batchedMultiGet(opts *ReadOptions, cCf *C.rocksdb_column_family_handle_t, sortedInput bool, keys [][]byte) (PinnableSliceHandles, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
 	cKeys, cKeySizes := byteSlicesToCSlices(keys)
 	defer cKeys.Destroy()
-	vals := make(charsSlice, len(keys))
-	valSizes := make(sizeTSlice, len(keys))
+	vals := make([]*C.rocksdb_pinnableslice_t, len(keys))
 	rocksErrs := make(charsSlice, len(keys))
 
-	C.rocksdb_multi_get_cf(
+	C.rocksdb_batched_multi_get_cf(
 		db.c,
 		opts.c,
-		cfs.toCSlice().c(),
+		cCf,
 		C.size_t(len(keys)),
 		cKeys.c(),
 		cKeySizes.c(),
-		vals.c(),
-		valSizes.c(),
+		&vals[0],
 		rocksErrs.c(),
+		C.bool(sortedInput),
 	)
 
+	handles := make(PinnableSliceHandles, len(keys))
+	for i, val := range vals {
+		handles[i] = NewNativePinnableSliceHandle(val)
+	}
+
 	var errs []error
 
 	for i, rocksErr := range rocksErrs {
 		if rocksErr != nil {
 			defer C.rocksdb_free(unsafe.Pointer(rocksErr))
-			err := fmt.Errorf("getting %q failed: %v", string(keys[i]), C.GoString(rocksErr))
+			err := fmt.Errorf("getting %q failed: %w", string(keys[i]), newError(C.GoString(rocksErr)))
 			errs = append(errs, err)
 		}
 	}
 
 	if len(errs) > 0 {
-		return nil, fmt.Errorf("failed to get %d keys, first error: %v", len(errs), errs[0])
-	}
-
-	slices := make(Slices, len(keys))
-	for i, val := range vals {
-		slices[i] = NewSlice(val, valSizes[i])
+		handles.Destroy()
+		return nil, fmt.Errorf("failed to get %d keys, first error: %w", len(errs), errs[0])
 	}
 
-	return slices, nil
+	return handles, nil
 }
 
 // Put writes data associated with a key to the database.
@@ -403,7 +485,7 @@ func (db *DB) Put(opts *WriteOptions, key, value []byte) error {
 	C.rocksdb_put(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -418,7 +500,7 @@ func (db *DB) PutCF(opts *WriteOptions, cf *ColumnFamilyHandle, key, value []byt
 	C.rocksdb_put_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -432,7 +514,7 @@ func (db *DB) Delete(opts *WriteOptions, key []byte) error {
 	C.rocksdb_delete(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -446,7 +528,66 @@ func (db *DB) DeleteCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte) e
 	C.rocksdb_delete_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// SingleDelete removes the data associated with the key from the database.
+// Unlike Delete, the tombstone is dropped together with the value during
+// compaction. It requires that the key was written exactly once since the
+// last delete and was not merged, otherwise the result is undefined.
+func (db *DB) SingleDelete(opts *WriteOptions, key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.rocksdb_singledelete(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// SingleDeleteCF removes the data associated with the key from the database
+// and column family, see SingleDelete.
+func (db *DB) SingleDeleteCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.rocksdb_singledelete_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// DeleteRange removes the keys in [startKey, endKey) from the database.
+func (db *DB) DeleteRange(opts *WriteOptions, startKey, endKey []byte) error {
+	cCf := C.rocksdb_get_default_column_family_handle(db.c)
+	defer C.rocksdb_column_family_handle_destroy(cCf)
+	return db.deleteRange(opts, cCf, startKey, endKey)
+}
+
+// DeleteRangeCF removes the keys in [startKey, endKey) from the database and
+// column family.
+func (db *DB) DeleteRangeCF(opts *WriteOptions, cf *ColumnFamilyHandle, startKey, endKey []byte) error {
+	return db.deleteRange(opts, cf.c, startKey, endKey)
+}
+
+func (db *DB) deleteRange(opts *WriteOptions, cCf *C.rocksdb_column_family_handle_t, startKey, endKey []byte) error {
+	var (
+		cErr      *C.char
+		cStartKey = byteToChar(startKey)
+		cEndKey   = byteToChar(endKey)
+	)
+	C.rocksdb_delete_range_cf(db.c, opts.c, cCf, cStartKey, C.size_t(len(startKey)), cEndKey, C.size_t(len(endKey)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -461,7 +602,7 @@ func (db *DB) Merge(opts *WriteOptions, key []byte, value []byte) error {
 	C.rocksdb_merge(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -477,7 +618,7 @@ func (db *DB) MergeCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte, va
 	C.rocksdb_merge_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -488,7 +629,18 @@ func (db *DB) Write(opts *WriteOptions, batch *WriteBatch) error {
 	C.rocksdb_write(db.c, opts.c, batch.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// WriteWithIndex writes a WriteBatchWithIndex to the database
+func (db *DB) WriteWithIndex(opts *WriteOptions, batch *WriteBatchWithIndex) error {
+	var cErr *C.char
+	C.rocksdb_write_writebatch_wi(db.c, opts.c, batch.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -512,7 +664,7 @@ func (db *DB) GetUpdatesSince(seqNumber uint64) (*WalIterator, error) {
 	cIter := C.rocksdb_get_updates_since(db.c, C.uint64_t(seqNumber), nil, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return NewNativeWalIterator(unsafe.Pointer(cIter)), nil
 }
@@ -561,9 +713,9 @@ func (db *DB) CreateColumnFamily(opts *Options, name string) (*ColumnFamilyHandl
 	cHandle := C.rocksdb_create_column_family(db.c, opts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
-	return NewNativeColumnFamilyHandle(cHandle), nil
+	return &ColumnFamilyHandle{c: cHandle, opts: opts}, nil
 }
 
 // DropColumnFamily drops a column family.
@@ -572,7 +724,7 @@ func (db *DB) DropColumnFamily(c *ColumnFamilyHandle) error {
 	C.rocksdb_drop_column_family(db.c, c.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -686,41 +838,57 @@ func (db *DB) SetOptions(keys, values []string) error {
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
 
 // LiveFileMetadata is a metadata which is associated with each SST file.
 type LiveFileMetadata struct {
-	Name        string
-	Level       int
-	Size        int64
-	SmallestKey []byte
-	LargestKey  []byte
+	Name             string
+	ColumnFamilyName string
+	Level            int
+	Size             int64
+	SmallestKey      []byte
+	LargestKey       []byte
+	SmallestSeqno    uint64
+	LargestSeqno     uint64
+	NumEntries       uint64
+	NumDeletions     uint64
+	BeingCompacted   bool
+	FileCreationTime time.Time
 }
 
 // GetLiveFilesMetaData returns a list of all table files with their
-// level, start key and end key.
+// column family, level, key range and counters.
 func (db *DB) GetLiveFilesMetaData() []LiveFileMetadata {
-	lf := C.rocksdb_livefiles(db.c)
-	defer C.rocksdb_livefiles_destroy(lf)
-
-	count := C.rocksdb_livefiles_count(lf)
-	liveFiles := make([]LiveFileMetadata, int(count))
-	for i := C.int(0); i < count; i++ {
-		var liveFile LiveFileMetadata
-		liveFile.Name = C.GoString(C.rocksdb_livefiles_name(lf, i))
-		liveFile.Level = int(C.rocksdb_livefiles_level(lf, i))
-		liveFile.Size = int64(C.rocksdb_livefiles_size(lf, i))
-
-		var cSize C.size_t
-		key := C.rocksdb_livefiles_smallestkey(lf, i, &cSize)
-		liveFile.SmallestKey = C.GoBytes(unsafe.Pointer(key), C.int(cSize))
-
-		key = C.rocksdb_livefiles_largestkey(lf, i, &cSize)
-		liveFile.LargestKey = C.GoBytes(unsafe.Pointer(key), C.int(cSize))
-		liveFiles[int(i)] = liveFile
+	lf := C.gorocksdb_livefiles(db.c)
+	defer C.gorocksdb_livefiles_destroy(lf)
+
+	liveFiles := make([]LiveFileMetadata, int(C.gorocksdb_livefiles_count(lf)))
+	for i := range liveFiles {
+		var (
+			cMeta   C.gorocksdb_sstfilemetadata_t
+			cCfName *C.char
+			cLevel  C.int
+		)
+		C.gorocksdb_livefiles_metadata(lf, C.size_t(i), &cCfName, &cLevel, &cMeta)
+		meta := newSstFileMetadata(&cMeta)
+
+		liveFiles[i] = LiveFileMetadata{
+			Name:             meta.Name,
+			ColumnFamilyName: C.GoString(cCfName),
+			Level:            int(cLevel),
+			Size:             meta.Size,
+			SmallestKey:      meta.SmallestKey,
+			LargestKey:       meta.LargestKey,
+			SmallestSeqno:    meta.SmallestSeqno,
+			LargestSeqno:     meta.LargestSeqno,
+			NumEntries:       meta.NumEntries,
+			NumDeletions:     meta.NumDeletions,
+			BeingCompacted:   meta.BeingCompacted,
+			FileCreationTime: meta.FileCreationTime,
+		}
 	}
 	return liveFiles
 }
@@ -741,13 +909,134 @@ func (db *DB) CompactRangeCF(cf *ColumnFamilyHandle, r Range) {
 	C.rocksdb_compact_range_cf(db.c, cf.c, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)))
 }
 
+// CompactRangeWithOptions runs a manual compaction on the Range of keys
+// given, controlled by opts. Unlike CompactRange, which keeps its signature
+// for API compatibility, it returns the error of the compaction.
+func (db *DB) CompactRangeWithOptions(opts *CompactRangeOptions, r Range) error {
+	return db.compactRange(opts, nil, r, nil)
+}
+
+// CompactRangeCFWithOptions runs a manual compaction on the Range of keys
+// given on the given column family, controlled by opts. Unlike
+// CompactRangeCF, which keeps its signature for API compatibility, it
+// returns the error of the compaction.
+func (db *DB) CompactRangeCFWithOptions(opts *CompactRangeOptions, cf *ColumnFamilyHandle, r Range) error {
+	return db.compactRange(opts, cf.c, r, nil)
+}
+
+// CompactFiles compacts the given SST files of the default column family
+// into outputLevel. The names are those returned by GetLiveFilesMetaData.
+func (db *DB) CompactFiles(names []string, outputLevel int) error {
+	return db.compactFiles(nil, names, outputLevel)
+}
+
+// CompactFilesCF compacts the given SST files of the column family into
+// outputLevel. The names are those returned by GetLiveFilesMetaData.
+func (db *DB) CompactFilesCF(cf *ColumnFamilyHandle, names []string, outputLevel int) error {
+	return db.compactFiles(cf.c, names, outputLevel)
+}
+
+func (db *DB) compactFiles(cCf *C.rocksdb_column_family_handle_t, names []string, outputLevel int) error {
+	cNames := make(charsSlice, len(names))
+	for i, name := range names {
+		cNames[i] = C.CString(name)
+	}
+	defer cNames.Destroy()
+
+	var cErr *C.char
+	C.gorocksdb_compact_files(db.c, cCf, cNames.c(), C.size_t(len(names)), C.int(outputLevel), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// CompactRangeContext runs a manual compaction like
+// CompactRangeWithOptions, using the default options if opts is nil. If ctx
+// is done before the compaction finished, only this compaction is canceled
+// and ctx.Err() is returned.
+func (db *DB) CompactRangeContext(ctx context.Context, opts *CompactRangeOptions, r Range) error {
+	return db.compactRangeContext(ctx, opts, nil, r)
+}
+
+// CompactRangeCFContext runs a manual compaction on the given column family,
+// which is canceled like CompactRangeContext if ctx is done.
+func (db *DB) CompactRangeCFContext(ctx context.Context, opts *CompactRangeOptions, cf *ColumnFamilyHandle, r Range) error {
+	return db.compactRangeContext(ctx, opts, cf.c, r)
+}
+
+func (db *DB) compactRangeContext(ctx context.Context, opts *CompactRangeOptions, cCf *C.rocksdb_column_family_handle_t, r Range) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	cCanceled := C.gorocksdb_cancel_flag_create()
+	defer C.gorocksdb_cancel_flag_destroy(cCanceled)
+	return runContext(ctx, func() error {
+		return db.compactRange(opts, cCf, r, cCanceled)
+	}, func() {
+		C.gorocksdb_cancel_flag_set(cCanceled)
+	})
+}
+
+// compactRange runs a manual compaction with opts, or the default options if
+// opts is nil. The compaction is canceled once cCanceled is set, if it is
+// not nil.
+func (db *DB) compactRange(opts *CompactRangeOptions, cCf *C.rocksdb_column_family_handle_t, r Range, cCanceled *C.gorocksdb_cancel_flag_t) error {
+	var (
+		cErr  *C.char
+		cOpts *C.rocksdb_compactoptions_t
+	)
+	if opts != nil {
+		cOpts = opts.c
+	}
+	cStart := byteToChar(r.Start)
+	cLimit := byteToChar(r.Limit)
+	C.gorocksdb_compact_range(db.c, cCf, cOpts, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)), cCanceled, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
 // Flush triggers a manuel flush for the database.
 func (db *DB) Flush(opts *FlushOptions) error {
 	var cErr *C.char
 	C.rocksdb_flush(db.c, opts.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// flushPollInterval is how often FlushContext checks if the flush finished.
+const flushPollInterval = 10 * time.Millisecond
+
+// FlushContext triggers a manual flush for the database and waits until all
+// pending flushes finished. If ctx is done before, ctx.Err() is returned
+// and the flush continues in the background.
+func (db *DB) FlushContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	opts := NewDefaultFlushOptions()
+	defer opts.Destroy()
+	opts.SetWait(false)
+	if err := db.Flush(opts); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(flushPollInterval)
+	defer ticker.Stop()
+	for db.GetProperty("rocksdb.mem-table-flush-pending") != "0" ||
+		db.GetProperty("rocksdb.num-running-flushes") != "0" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
 	return nil
 }
@@ -758,7 +1047,7 @@ func (db *DB) DisableFileDeletions() error {
 	C.rocksdb_disable_file_deletions(db.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -769,7 +1058,7 @@ func (db *DB) EnableFileDeletions(force bool) error {
 	C.rocksdb_enable_file_deletions(db.c, boolToChar(force), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -799,7 +1088,7 @@ func (db *DB) DeleteFileInRange(r Range) error {
 
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -822,7 +1111,7 @@ func (db *DB) DeleteFileInRangeCF(cf *ColumnFamilyHandle, r Range) error {
 
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -851,7 +1140,7 @@ func (db *DB) IngestExternalFile(filePaths []string, opts *IngestExternalFileOpt
 
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -881,11 +1170,31 @@ func (db *DB) IngestExternalFileCF(handle *ColumnFamilyHandle, filePaths []strin
 
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
 
+// IngestExternalFileContext loads a list of external SST files like
+// IngestExternalFile unless ctx is already done, in which case ctx.Err()
+// is returned. ctx is only checked before the ingestion starts; an
+// ingestion cannot be canceled once it started.
+func (db *DB) IngestExternalFileContext(ctx context.Context, filePaths []string, opts *IngestExternalFileOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return db.IngestExternalFile(filePaths, opts)
+}
+
+// IngestExternalFileCFContext loads a list of external SST files for a
+// column family like IngestExternalFileContext.
+func (db *DB) IngestExternalFileCFContext(ctx context.Context, handle *ColumnFamilyHandle, filePaths []string, opts *IngestExternalFileOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return db.IngestExternalFileCF(handle, filePaths, opts)
+}
+
 // NewCheckpoint creates a new Checkpoint for this db.
 func (db *DB) NewCheckpoint() (*Checkpoint, error) {
 	var (
@@ -896,7 +1205,7 @@ func (db *DB) NewCheckpoint() (*Checkpoint, error) {
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 
 	return NewNativeCheckpoint(cCheckpoint), nil
@@ -918,7 +1227,7 @@ func DestroyDb(name string, opts *Options) error {
 	C.rocksdb_destroy_db(opts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -933,7 +1242,7 @@ func RepairDb(name string, opts *Options) error {
 	C.rocksdb_repair_db(opts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
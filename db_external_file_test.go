@@ -1,6 +1,7 @@
 package gorocksdb
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -55,3 +56,66 @@ func TestExternalFile(t *testing.T) {
 	ensure.Nil(t, err)
 	ensure.DeepEqual(t, v4.Data(), []byte("dddValue"))
 }
+
+func TestExternalFileEntries(t *testing.T) {
+	db := newTestDB(t, "TestDBExternalFileEntries", func(opts *Options) {
+		opts.SetMergeOperator(&mockMergeOperator{
+			fullMerge: func(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+				return append(existingValue, bytes.Join(operands, nil)...), true
+			},
+		})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("aaa"), []byte("aaaValue")))
+	ensure.Nil(t, db.Put(wo, []byte("bbb"), []byte("bbbValue")))
+	ensure.Nil(t, db.Put(wo, []byte("ddd"), []byte("dddValue")))
+	ensure.Nil(t, db.Put(wo, []byte("eee"), []byte("eeeValue")))
+
+	envOpts := NewDefaultEnvOptions()
+	opts := NewDefaultOptions()
+	w := NewSSTFileWriter(envOpts, opts)
+	defer w.Destroy()
+
+	filePath, err := ioutil.TempFile("", "sst-file-test")
+	ensure.Nil(t, err)
+	defer os.Remove(filePath.Name())
+
+	ensure.Nil(t, w.Open(filePath.Name()))
+	ensure.Nil(t, w.Merge([]byte("aaa"), []byte("Merged")))
+	ensure.Nil(t, w.Delete([]byte("bbb")))
+	ensure.Nil(t, w.Put([]byte("ccc"), []byte("cccValue")))
+	ensure.Nil(t, w.DeleteRange([]byte("ddd"), []byte("fff")))
+
+	info, err := w.FinishWithInfo()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, info.FilePath, filePath.Name())
+	ensure.DeepEqual(t, info.SmallestKey, []byte("aaa"))
+	ensure.DeepEqual(t, info.LargestKey, []byte("ccc"))
+	ensure.DeepEqual(t, info.SmallestRangeDelKey, []byte("ddd"))
+	ensure.DeepEqual(t, info.LargestRangeDelKey, []byte("fff"))
+	ensure.DeepEqual(t, info.NumEntries, uint64(3))
+	ensure.DeepEqual(t, info.NumRangeDelEntries, uint64(1))
+	ensure.True(t, info.FileSize > 0)
+	ensure.DeepEqual(t, w.FileSize(), info.FileSize)
+
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	ensure.Nil(t, db.IngestExternalFile([]string{filePath.Name()}, ingestOpts))
+
+	readOpts := NewDefaultReadOptions()
+	v1, err := db.Get(readOpts, []byte("aaa"))
+	defer v1.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1.Data(), []byte("aaaValueMerged"))
+	for _, key := range []string{"bbb", "ddd", "eee"} {
+		v, err := db.Get(readOpts, []byte(key))
+		ensure.Nil(t, err)
+		ensure.True(t, v.Data() == nil)
+		v.Free()
+	}
+	v3, err := db.Get(readOpts, []byte("ccc"))
+	defer v3.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v3.Data(), []byte("cccValue"))
+}
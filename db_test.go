@@ -1,6 +1,7 @@
 package gorocksdb
 
 import (
+	"context"
 	"io/ioutil"
 	"strconv"
 	"testing"
@@ -205,6 +206,168 @@ func TestDBMultiGet(t *testing.T) {
 	ensure.DeepEqual(t, values[3].Data(), givenVal3)
 }
 
+func TestDBBatchedMultiGet(t *testing.T) {
+	db := newTestDB(t, "TestDBBatchedMultiGet", nil)
+	defer db.Close()
+
+	var (
+		givenKey1 = []byte("hello1")
+		givenKey2 = []byte("hello2")
+		givenVal1 = []byte("world1")
+		givenVal2 = []byte("world2")
+		wo        = NewDefaultWriteOptions()
+		ro        = NewDefaultReadOptions()
+	)
+
+	// create
+	ensure.Nil(t, db.Put(wo, givenKey1, givenVal1))
+	ensure.Nil(t, db.Put(wo, givenKey2, givenVal2))
+
+	// retrieve
+	values, err := db.BatchedMultiGet(ro, true, givenKey1, givenKey2, []byte("noexist"))
+	defer values.Destroy()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(values), 3)
+
+	ensure.DeepEqual(t, values[0].Data(), givenVal1)
+	ensure.DeepEqual(t, values[1].Data(), givenVal2)
+	ensure.False(t, values[2].Exists())
+	ensure.DeepEqual(t, values[2].Data(), []byte(nil))
+}
+
+func TestDBKeyMayExist(t *testing.T) {
+	db := newTestDB(t, "TestDBKeyMayExist", nil)
+	defer db.Close()
+
+	var (
+		givenKey = []byte("hello")
+		givenVal = []byte("world")
+		wo       = NewDefaultWriteOptions()
+		ro       = NewDefaultReadOptions()
+	)
+
+	ensure.False(t, db.KeyMayExist(ro, givenKey))
+	ensure.Nil(t, db.Put(wo, givenKey, givenVal))
+	ensure.True(t, db.KeyMayExist(ro, givenKey))
+
+	// the value is in the memtable
+	mayExist, value := db.KeyMayExistWithValue(ro, givenKey)
+	ensure.True(t, mayExist)
+	ensure.NotNil(t, value)
+	defer value.Free()
+	ensure.DeepEqual(t, value.Data(), givenVal)
+
+	mayExist, value = db.KeyMayExistWithValue(ro, []byte("noexist"))
+	ensure.False(t, mayExist)
+	ensure.True(t, value == nil)
+}
+
+func TestDBSingleDeleteAndDeleteRange(t *testing.T) {
+	db := newTestDB(t, "TestDBSingleDeleteAndDeleteRange", nil)
+	defer db.Close()
+
+	var (
+		wo = NewDefaultWriteOptions()
+		ro = NewDefaultReadOptions()
+	)
+	for _, k := range []string{"key1", "key2", "key3", "key4"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte("val")))
+	}
+
+	ensure.Nil(t, db.SingleDelete(wo, []byte("key1")))
+	ensure.Nil(t, db.DeleteRange(wo, []byte("key2"), []byte("key4")))
+
+	values, err := db.MultiGet(ro, []byte("key1"), []byte("key2"), []byte("key3"), []byte("key4"))
+	defer values.Destroy()
+	ensure.Nil(t, err)
+	ensure.False(t, values[0].Exists())
+	ensure.False(t, values[1].Exists())
+	ensure.False(t, values[2].Exists())
+	ensure.DeepEqual(t, values[3].Data(), []byte("val"))
+}
+
+func TestDBCompactFiles(t *testing.T) {
+	db := newTestDB(t, "TestDBCompactFiles", nil)
+	defer db.Close()
+
+	var (
+		wo = NewDefaultWriteOptions()
+		ro = NewDefaultReadOptions()
+		fo = NewDefaultFlushOptions()
+	)
+	for _, k := range []string{"key1", "key2"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte("val")))
+		ensure.Nil(t, db.Flush(fo))
+	}
+
+	var names []string
+	for _, f := range db.GetLiveFilesMetaData() {
+		ensure.DeepEqual(t, f.Level, 0)
+		names = append(names, f.Name)
+	}
+	ensure.DeepEqual(t, len(names), 2)
+
+	ensure.Nil(t, db.CompactFiles(names, 1))
+	files := db.GetLiveFilesMetaData()
+	ensure.DeepEqual(t, len(files), 1)
+	ensure.DeepEqual(t, files[0].Level, 1)
+
+	opts := NewCompactRangeOptions()
+	defer opts.Destroy()
+	opts.SetBottommostLevelCompaction(BottommostLevelCompactionForce)
+	opts.SetExclusiveManualCompaction(false)
+	opts.SetAllowWriteStall(true)
+	opts.SetMaxSubcompactions(2)
+	ensure.Nil(t, db.CompactRangeWithOptions(opts, Range{nil, nil}))
+	ensure.DeepEqual(t, len(db.GetLiveFilesMetaData()), 1)
+
+	values, err := db.MultiGet(ro, []byte("key1"), []byte("key2"))
+	defer values.Destroy()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, values[0].Data(), []byte("val"))
+	ensure.DeepEqual(t, values[1].Data(), []byte("val"))
+}
+
+func TestDBColumnFamilyMetadata(t *testing.T) {
+	db := newTestDB(t, "TestDBColumnFamilyMetadata", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val")))
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("val")))
+	ensure.Nil(t, db.Delete(wo, []byte("key3")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+
+	liveFiles := db.GetLiveFilesMetaData()
+	ensure.DeepEqual(t, len(liveFiles), 1)
+	liveFile := liveFiles[0]
+	ensure.DeepEqual(t, liveFile.ColumnFamilyName, "default")
+	ensure.DeepEqual(t, liveFile.Level, 0)
+	ensure.DeepEqual(t, liveFile.SmallestKey, []byte("key1"))
+	ensure.DeepEqual(t, liveFile.LargestKey, []byte("key3"))
+	ensure.DeepEqual(t, liveFile.SmallestSeqno, uint64(1))
+	ensure.DeepEqual(t, liveFile.LargestSeqno, uint64(3))
+	ensure.DeepEqual(t, liveFile.NumEntries, uint64(3))
+	ensure.DeepEqual(t, liveFile.NumDeletions, uint64(1))
+	ensure.False(t, liveFile.BeingCompacted)
+
+	meta := db.GetColumnFamilyMetaData()
+	ensure.DeepEqual(t, meta.Name, "default")
+	ensure.DeepEqual(t, meta.FileCount, 1)
+	ensure.True(t, len(meta.Levels) > 1)
+	ensure.DeepEqual(t, meta.Levels[0].Level, 0)
+	ensure.DeepEqual(t, len(meta.Levels[0].Files), 1)
+	ensure.DeepEqual(t, len(meta.Levels[1].Files), 0)
+
+	file := meta.Levels[0].Files[0]
+	ensure.DeepEqual(t, file.Name, liveFile.Name)
+	ensure.DeepEqual(t, file.Size, liveFile.Size)
+	ensure.DeepEqual(t, meta.Size, file.Size)
+	ensure.DeepEqual(t, file.NumEntries, uint64(3))
+	ensure.DeepEqual(t, file.NumDeletions, uint64(1))
+	ensure.DeepEqual(t, file.FileCreationTime, liveFile.FileCreationTime)
+}
+
 func TestDBGetApproximateSizes(t *testing.T) {
 	db := newTestDB(t, "TestDBGetApproximateSizes", nil)
 	defer db.Close()
@@ -243,3 +406,32 @@ func TestDBGetApproximateSizesCF(t *testing.T) {
 	sizes = db.GetApproximateSizesCF(cf, []Range{{Start: []byte{0x00}, Limit: []byte{0xFF}}})
 	ensure.DeepEqual(t, sizes, []uint64{0})
 }
+
+func TestDBContext(t *testing.T) {
+	db := newTestDB(t, "TestDBContext", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("val")))
+
+	ctx := context.Background()
+	ensure.Nil(t, db.FlushContext(ctx))
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "1")
+	ensure.Nil(t, db.CompactRangeContext(ctx, nil, Range{nil, nil}))
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "0")
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	ensure.DeepEqual(t, db.FlushContext(canceled), context.Canceled)
+	ensure.DeepEqual(t, db.CompactRangeContext(canceled, nil, Range{nil, nil}), context.Canceled)
+	ensure.DeepEqual(t, db.IngestExternalFileContext(canceled, nil, NewDefaultIngestExternalFileOptions()), context.Canceled)
+
+	// a canceled compaction does not affect other compactions
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("val")))
+	ensure.Nil(t, db.FlushContext(ctx))
+	opts := NewCompactRangeOptions()
+	defer opts.Destroy()
+	opts.SetBottommostLevelCompaction(BottommostLevelCompactionForce)
+	ensure.Nil(t, db.CompactRangeContext(ctx, opts, Range{nil, nil}))
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "0")
+}
@@ -1,11 +1,15 @@
 package gorocksdb
 
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
 // Env is a system call environment used by a database.
 type Env struct {
 	c *C.rocksdb_env_t
+
+	// ext is set instead of c for the envs implemented by gorocksdb.
+	ext *C.gorocksdb_env_t
 }
 
 // NewDefaultEnv creates a default environment.
@@ -20,7 +24,7 @@ func NewMemEnv() *Env {
 
 // NewNativeEnv creates a Environment object.
 func NewNativeEnv(c *C.rocksdb_env_t) *Env {
-	return &Env{c}
+	return &Env{c: c}
 }
 
 // SetBackgroundThreads sets the number of background worker threads
@@ -28,6 +32,10 @@ func NewNativeEnv(c *C.rocksdb_env_t) *Env {
 // 'LOW' is the default pool.
 // Default: 1
 func (env *Env) SetBackgroundThreads(n int) {
+	if env.ext != nil {
+		C.gorocksdb_env_set_background_threads(env.ext, C.int(n))
+		return
+	}
 	C.rocksdb_env_set_background_threads(env.c, C.int(n))
 }
 
@@ -35,11 +43,20 @@ func (env *Env) SetBackgroundThreads(n int) {
 // thread pool that can be used to prevent compactions from stalling
 // memtable flushes.
 func (env *Env) SetHighPriorityBackgroundThreads(n int) {
+	if env.ext != nil {
+		C.gorocksdb_env_set_high_priority_background_threads(env.ext, C.int(n))
+		return
+	}
 	C.rocksdb_env_set_high_priority_background_threads(env.c, C.int(n))
 }
 
 // Destroy deallocates the Env object.
 func (env *Env) Destroy() {
+	if env.ext != nil {
+		C.gorocksdb_env_destroy(env.ext)
+		env.ext = nil
+		return
+	}
 	C.rocksdb_env_destroy(env.c)
 	env.c = nil
 }
@@ -0,0 +1,116 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+// FileOpenMode describes how a file is opened by the database.
+type FileOpenMode int
+
+const (
+	// FileOpenSequential opens a file for sequential reading, e.g. a WAL
+	// or MANIFEST during recovery.
+	FileOpenSequential = FileOpenMode(C.GOROCKSDB_FILE_OPEN_SEQUENTIAL)
+	// FileOpenRandomAccess opens a file for random reads, e.g. an SST file.
+	FileOpenRandomAccess = FileOpenMode(C.GOROCKSDB_FILE_OPEN_RANDOM_ACCESS)
+	// FileOpenWritable creates, reopens or reuses a file for writing.
+	FileOpenWritable = FileOpenMode(C.GOROCKSDB_FILE_OPEN_WRITABLE)
+)
+
+// EnvHooks are callbacks on the file operations of an Env created by
+// NewHookedEnv. Nil callbacks are not called, so the default behavior is
+// kept without any overhead. The callbacks are called from the threads of
+// the database and must be thread-safe.
+//
+// The callbacks returning an error are called before the operation. A
+// non-nil error fails the operation with an IO error, which can be used to
+// enforce quotas or inject faults. The other callbacks are called after the
+// operation succeeded.
+type EnvHooks struct {
+	// Open is called before a file is opened.
+	Open func(name string, mode FileOpenMode) error
+
+	// Read is called after n bytes were read from a file.
+	Read func(name string, n int)
+
+	// Write is called before n bytes are appended to a file.
+	Write func(name string, n int) error
+
+	// Sync is called after a file was synced.
+	Sync func(name string)
+
+	// Rename is called before the file src is renamed to target.
+	Rename func(src, target string) error
+
+	// Delete is called before a file is deleted.
+	Delete func(name string) error
+}
+
+// Hold references to env hooks.
+var envHooks = NewCOWList()
+
+// NewHookedEnv creates an environment which wraps the default environment
+// and calls hooks on file operations, e.g. to measure I/O per file type.
+// Set it with Options.SetEnv.
+func NewHookedEnv(hooks EnvHooks) *Env {
+	var mask C.uchar
+	if hooks.Open != nil {
+		mask |= C.GOROCKSDB_ENV_HOOK_OPEN
+	}
+	if hooks.Read != nil {
+		mask |= C.GOROCKSDB_ENV_HOOK_READ
+	}
+	if hooks.Write != nil {
+		mask |= C.GOROCKSDB_ENV_HOOK_WRITE
+	}
+	if hooks.Sync != nil {
+		mask |= C.GOROCKSDB_ENV_HOOK_SYNC
+	}
+	if hooks.Rename != nil {
+		mask |= C.GOROCKSDB_ENV_HOOK_RENAME
+	}
+	if hooks.Delete != nil {
+		mask |= C.GOROCKSDB_ENV_HOOK_DELETE
+	}
+	idx := envHooks.Append(hooks)
+	return &Env{ext: C.gorocksdb_create_hooked_env(C.uintptr_t(idx), mask)}
+}
+
+// envHookError converts the error of a hook to a message which is released
+// by the caller.
+func envHookError(err error) *C.char {
+	if err == nil {
+		return nil
+	}
+	return C.CString(err.Error())
+}
+
+//export gorocksdb_env_open
+func gorocksdb_env_open(idx int, cName *C.char, cMode C.int) *C.char {
+	return envHookError(envHooks.Get(idx).(EnvHooks).Open(C.GoString(cName), FileOpenMode(cMode)))
+}
+
+//export gorocksdb_env_read
+func gorocksdb_env_read(idx int, cName *C.char, cN C.size_t) {
+	envHooks.Get(idx).(EnvHooks).Read(C.GoString(cName), int(cN))
+}
+
+//export gorocksdb_env_write
+func gorocksdb_env_write(idx int, cName *C.char, cN C.size_t) *C.char {
+	return envHookError(envHooks.Get(idx).(EnvHooks).Write(C.GoString(cName), int(cN)))
+}
+
+//export gorocksdb_env_sync
+func gorocksdb_env_sync(idx int, cName *C.char) {
+	envHooks.Get(idx).(EnvHooks).Sync(C.GoString(cName))
+}
+
+//export gorocksdb_env_rename
+func gorocksdb_env_rename(idx int, cSrc *C.char, cTarget *C.char) *C.char {
+	return envHookError(envHooks.Get(idx).(EnvHooks).Rename(C.GoString(cSrc), C.GoString(cTarget)))
+}
+
+//export gorocksdb_env_delete
+func gorocksdb_env_delete(idx int, cName *C.char) *C.char {
+	return envHookError(envHooks.Get(idx).(EnvHooks).Delete(C.GoString(cName)))
+}
@@ -0,0 +1,105 @@
+package gorocksdb
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestHookedEnv(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		opened  = make(map[string]FileOpenMode)
+		written = make(map[string]int)
+		read    int
+		synced  int
+	)
+	env := NewHookedEnv(EnvHooks{
+		Open: func(name string, mode FileOpenMode) error {
+			mu.Lock()
+			defer mu.Unlock()
+			opened[filepath.Ext(name)] = mode
+			return nil
+		},
+		Read: func(name string, n int) {
+			mu.Lock()
+			defer mu.Unlock()
+			read += n
+		},
+		Write: func(name string, n int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			written[filepath.Ext(name)] += n
+			return nil
+		},
+		Sync: func(name string) {
+			mu.Lock()
+			defer mu.Unlock()
+			synced++
+		},
+	})
+	defer env.Destroy()
+
+	db := newTestDB(t, "TestHookedEnv", func(opts *Options) {
+		opts.SetEnv(env)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("val")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+
+	// read the flushed table
+	ro := NewDefaultReadOptions()
+	ro.SetFillCache(false)
+	v, err := db.Get(ro, []byte("key"))
+	defer v.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("val"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	ensure.DeepEqual(t, opened[".log"], FileOpenWritable)
+	ensure.True(t, written[".log"] > 0)
+	ensure.True(t, written[".sst"] > 0)
+	ensure.True(t, read > 0)
+	ensure.True(t, synced > 0)
+}
+
+func TestHookedEnvQuota(t *testing.T) {
+	errQuota := errors.New("quota exceeded")
+	var (
+		mu   sync.Mutex
+		full bool
+	)
+	env := NewHookedEnv(EnvHooks{
+		Write: func(name string, n int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if full && strings.HasSuffix(name, ".log") {
+				return errQuota
+			}
+			return nil
+		},
+	})
+	defer env.Destroy()
+
+	db := newTestDB(t, "TestHookedEnvQuota", func(opts *Options) {
+		opts.SetEnv(env)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val")))
+
+	mu.Lock()
+	full = true
+	mu.Unlock()
+	err := db.Put(wo, []byte("key2"), []byte("val"))
+	ensure.True(t, errors.Is(err, ErrIOError))
+	ensure.True(t, strings.Contains(err.Error(), errQuota.Error()))
+}
@@ -0,0 +1,183 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "gorocksdb.h"
+import "C"
+import (
+	"strings"
+	"unsafe"
+)
+
+// ErrorCode is the code of a RocksDB status.
+type ErrorCode int
+
+// Error codes, matching rocksdb::Status::Code.
+const (
+	// CodeUnknown is used if the status message could not be parsed.
+	CodeUnknown             = ErrorCode(-1)
+	CodeNotFound            = ErrorCode(1)
+	CodeCorruption          = ErrorCode(2)
+	CodeNotSupported        = ErrorCode(3)
+	CodeInvalidArgument     = ErrorCode(4)
+	CodeIOError             = ErrorCode(5)
+	CodeMergeInProgress     = ErrorCode(6)
+	CodeIncomplete          = ErrorCode(7)
+	CodeShutdownInProgress  = ErrorCode(8)
+	CodeTimedOut            = ErrorCode(9)
+	CodeAborted             = ErrorCode(10)
+	CodeBusy                = ErrorCode(11)
+	CodeExpired             = ErrorCode(12)
+	CodeTryAgain            = ErrorCode(13)
+	CodeCompactionTooLarge  = ErrorCode(14)
+	CodeColumnFamilyDropped = ErrorCode(15)
+)
+
+// ErrorSubCode further specifies the reason of a RocksDB status.
+type ErrorSubCode int
+
+// Error subcodes, matching rocksdb::Status::SubCode.
+const (
+	SubCodeNone                              = ErrorSubCode(0)
+	SubCodeMutexTimeout                      = ErrorSubCode(1)
+	SubCodeLockTimeout                       = ErrorSubCode(2)
+	SubCodeLockLimit                         = ErrorSubCode(3)
+	SubCodeNoSpace                           = ErrorSubCode(4)
+	SubCodeDeadlock                          = ErrorSubCode(5)
+	SubCodeStaleFile                         = ErrorSubCode(6)
+	SubCodeMemoryLimit                       = ErrorSubCode(7)
+	SubCodeSpaceLimit                        = ErrorSubCode(8)
+	SubCodePathNotFound                      = ErrorSubCode(9)
+	SubCodeMergeOperandsInsufficientCapacity = ErrorSubCode(10)
+	SubCodeManualCompactionPaused            = ErrorSubCode(11)
+	SubCodeOverwritten                       = ErrorSubCode(12)
+	SubCodeTxnNotPrepared                    = ErrorSubCode(13)
+	SubCodeIOFenced                          = ErrorSubCode(14)
+)
+
+// Error is an error returned by RocksDB.
+//
+// Errors can be compared with the sentinel values using errors.Is, e.g.
+// errors.Is(err, ErrBusy) is true for every error with CodeBusy. Sentinels
+// with a subcode, like ErrDeadlock, only match errors with that subcode.
+type Error struct {
+	Code    ErrorCode
+	SubCode ErrorSubCode
+	msg     string
+}
+
+// Error returns the RocksDB status message.
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Is reports whether target is an *Error with the same code and, if the
+// subcode of target is set, the same subcode.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code && (t.SubCode == SubCodeNone || e.SubCode == t.SubCode)
+}
+
+// Sentinel errors for use with errors.Is.
+var (
+	ErrNotFound            = &Error{Code: CodeNotFound, msg: "not found"}
+	ErrCorruption          = &Error{Code: CodeCorruption, msg: "corruption"}
+	ErrNotSupported        = &Error{Code: CodeNotSupported, msg: "not supported"}
+	ErrInvalidArgument     = &Error{Code: CodeInvalidArgument, msg: "invalid argument"}
+	ErrIOError             = &Error{Code: CodeIOError, msg: "io error"}
+	ErrMergeInProgress     = &Error{Code: CodeMergeInProgress, msg: "merge in progress"}
+	ErrIncomplete          = &Error{Code: CodeIncomplete, msg: "result incomplete"}
+	ErrShutdownInProgress  = &Error{Code: CodeShutdownInProgress, msg: "shutdown in progress"}
+	ErrTimedOut            = &Error{Code: CodeTimedOut, msg: "operation timed out"}
+	ErrAborted             = &Error{Code: CodeAborted, msg: "operation aborted"}
+	ErrBusy                = &Error{Code: CodeBusy, msg: "resource busy"}
+	ErrExpired             = &Error{Code: CodeExpired, msg: "operation expired"}
+	ErrTryAgain            = &Error{Code: CodeTryAgain, msg: "try again"}
+	ErrCompactionTooLarge  = &Error{Code: CodeCompactionTooLarge, msg: "compaction too large"}
+	ErrColumnFamilyDropped = &Error{Code: CodeColumnFamilyDropped, msg: "column family dropped"}
+
+	ErrLockTimeout            = &Error{Code: CodeTimedOut, SubCode: SubCodeLockTimeout, msg: "lock timeout"}
+	ErrDeadlock               = &Error{Code: CodeBusy, SubCode: SubCodeDeadlock, msg: "deadlock"}
+	ErrNoSpace                = &Error{Code: CodeIOError, SubCode: SubCodeNoSpace, msg: "no space left on device"}
+	ErrManualCompactionPaused = &Error{Code: CodeIncomplete, SubCode: SubCodeManualCompactionPaused, msg: "manual compaction paused"}
+)
+
+// errorCodePrefixes are the prefixes rocksdb::Status::ToString uses.
+var errorCodePrefixes = []struct {
+	prefix string
+	code   ErrorCode
+}{
+	{"NotFound: ", CodeNotFound},
+	{"Corruption: ", CodeCorruption},
+	{"Not implemented: ", CodeNotSupported},
+	{"Invalid argument: ", CodeInvalidArgument},
+	{"IO error: ", CodeIOError},
+	{"Merge in progress: ", CodeMergeInProgress},
+	{"Result incomplete: ", CodeIncomplete},
+	{"Shutdown in progress: ", CodeShutdownInProgress},
+	{"Operation timed out: ", CodeTimedOut},
+	{"Operation aborted: ", CodeAborted},
+	{"Resource busy: ", CodeBusy},
+	{"Operation expired: ", CodeExpired},
+	{"Operation failed. Try again.: ", CodeTryAgain},
+	{"Compaction too large: ", CodeCompactionTooLarge},
+	{"Column family dropped: ", CodeColumnFamilyDropped},
+}
+
+// errorSubCodeMessages are the messages rocksdb::Status::ToString appends
+// after the prefix, indexed by subcode.
+var errorSubCodeMessages = []string{
+	SubCodeMutexTimeout:                      "Timeout Acquiring Mutex",
+	SubCodeLockTimeout:                       "Timeout waiting to lock key",
+	SubCodeLockLimit:                         "Failed to acquire lock due to max_num_locks limit",
+	SubCodeNoSpace:                           "No space left on device",
+	SubCodeDeadlock:                          "Deadlock",
+	SubCodeStaleFile:                         "Stale file handle",
+	SubCodeMemoryLimit:                       "Memory limit reached",
+	SubCodeSpaceLimit:                        "Space limit reached",
+	SubCodePathNotFound:                      "No such file or directory",
+	SubCodeMergeOperandsInsufficientCapacity: "Insufficient capacity for merge operands",
+	SubCodeManualCompactionPaused:            "Manual compaction paused",
+	SubCodeOverwritten:                       " (overwritten)",
+	SubCodeTxnNotPrepared:                    "Txn not prepared",
+	SubCodeIOFenced:                          "IO fenced off",
+}
+
+// newError creates an *Error from a RocksDB status message.
+func newError(msg string) error {
+	e := &Error{Code: CodeUnknown, msg: msg}
+	for _, p := range errorCodePrefixes {
+		if strings.HasPrefix(msg, p.prefix) {
+			e.Code = p.code
+			msg = msg[len(p.prefix):]
+			break
+		}
+	}
+	if e.Code == CodeUnknown {
+		return e
+	}
+	for subCode, subMsg := range errorSubCodeMessages {
+		if subMsg != "" && strings.HasPrefix(msg, subMsg) {
+			e.SubCode = ErrorSubCode(subCode)
+			break
+		}
+	}
+	return e
+}
+
+// newStatusError creates an *Error from a status returned by a gorocksdb
+// function, which carries the code and subcode, and releases its message.
+// It returns nil if the status is ok.
+func newStatusError(c *C.gorocksdb_status_t) error {
+	if c.msg == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(c.msg))
+	return &Error{
+		Code:    ErrorCode(c.code),
+		SubCode: ErrorSubCode(c.subcode),
+		msg:     C.GoString(c.msg),
+	}
+}
@@ -0,0 +1,58 @@
+package gorocksdb
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestNewError(t *testing.T) {
+	for _, c := range []struct {
+		msg     string
+		code    ErrorCode
+		subCode ErrorSubCode
+	}{
+		{"NotFound: ", CodeNotFound, SubCodeNone},
+		{"Corruption: block checksum mismatch", CodeCorruption, SubCodeNone},
+		{"Resource busy: ", CodeBusy, SubCodeNone},
+		{"Resource busy: Deadlock", CodeBusy, SubCodeDeadlock},
+		{"Operation timed out: Timeout waiting to lock key", CodeTimedOut, SubCodeLockTimeout},
+		{"Operation failed. Try again.: Transaction could not check for conflicts", CodeTryAgain, SubCodeNone},
+		{"IO error: No space left on device: /tmp/db/000012.sst", CodeIOError, SubCodeNoSpace},
+		{"Result incomplete: Manual compaction paused", CodeIncomplete, SubCodeManualCompactionPaused},
+		{"something else", CodeUnknown, SubCodeNone},
+	} {
+		err := newError(c.msg)
+		ensure.DeepEqual(t, err.Error(), c.msg)
+		var e *Error
+		ensure.True(t, errors.As(err, &e))
+		ensure.DeepEqual(t, e.Code, c.code, c.msg)
+		ensure.DeepEqual(t, e.SubCode, c.subCode, c.msg)
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	deadlock := newError("Resource busy: Deadlock")
+	ensure.True(t, errors.Is(deadlock, ErrBusy))
+	ensure.True(t, errors.Is(deadlock, ErrDeadlock))
+	ensure.False(t, errors.Is(deadlock, ErrTimedOut))
+
+	busy := newError("Resource busy: ")
+	ensure.True(t, errors.Is(busy, ErrBusy))
+	ensure.False(t, errors.Is(busy, ErrDeadlock))
+}
+
+func TestErrorFromDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestErrorFromDB")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetCreateIfMissing(false)
+	_, err = OpenDb(opts, dir)
+	ensure.True(t, errors.Is(err, ErrInvalidArgument))
+}
@@ -0,0 +1,251 @@
+package gorocksdb
+
+// #cgo CXXFLAGS: -std=c++17
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+// An EventListener is notified about background events of a database, such
+// as flushes, compactions and write stalls. It is registered with
+// Options.AddEventListener.
+//
+// The callbacks are invoked from RocksDB background threads, possibly
+// concurrently, and block the job that triggered them. They must therefore
+// be thread-safe and return quickly.
+type EventListener interface {
+	// OnFlushCompleted is called when a flush job has finished.
+	OnFlushCompleted(info FlushJobInfo)
+
+	// OnCompactionCompleted is called when a compaction job has finished.
+	OnCompactionCompleted(info CompactionJobInfo)
+
+	// OnStallConditionsChanged is called when the write stall condition of
+	// a column family changes.
+	OnStallConditionsChanged(info WriteStallInfo)
+
+	// OnBackgroundError is called when a background job fails and the
+	// database turns read-only.
+	OnBackgroundError(reason BackgroundErrorReason, err error)
+
+	// OnTableFileCreated is called when a SST file was created.
+	OnTableFileCreated(info TableFileCreationInfo)
+
+	// OnTableFileDeleted is called when a SST file was deleted.
+	OnTableFileDeleted(info TableFileDeletionInfo)
+}
+
+// FlushJobInfo describes a finished flush job.
+type FlushJobInfo struct {
+	ColumnFamilyName        string
+	FilePath                string
+	JobID                   int
+	TriggeredWritesSlowdown bool
+	TriggeredWritesStop     bool
+	SmallestSeqno           uint64
+	LargestSeqno            uint64
+	NumEntries              uint64
+}
+
+// CompactionReason describes why a compaction was triggered.
+type CompactionReason int
+
+// Compaction reasons.
+const (
+	CompactionReasonUnknown                    = CompactionReason(0)
+	CompactionReasonLevelL0FilesNum            = CompactionReason(1)
+	CompactionReasonLevelMaxLevelSize          = CompactionReason(2)
+	CompactionReasonUniversalSizeAmplification = CompactionReason(3)
+	CompactionReasonUniversalSizeRatio         = CompactionReason(4)
+	CompactionReasonUniversalSortedRunNum      = CompactionReason(5)
+	CompactionReasonFIFOMaxSize                = CompactionReason(6)
+	CompactionReasonFIFOReduceNumFiles         = CompactionReason(7)
+	CompactionReasonFIFOTtl                    = CompactionReason(8)
+	CompactionReasonManualCompaction           = CompactionReason(9)
+	CompactionReasonFilesMarkedForCompaction   = CompactionReason(10)
+	CompactionReasonBottommostFiles            = CompactionReason(11)
+	CompactionReasonTtl                        = CompactionReason(12)
+	CompactionReasonFlush                      = CompactionReason(13)
+	CompactionReasonExternalSstIngestion       = CompactionReason(14)
+)
+
+// CompactionJobInfo describes a finished compaction job.
+type CompactionJobInfo struct {
+	ColumnFamilyName string
+	// Err is non-nil if the compaction failed.
+	Err              error
+	JobID            int
+	BaseInputLevel   int
+	OutputLevel      int
+	InputFiles       []string
+	OutputFiles      []string
+	Reason           CompactionReason
+	TotalInputBytes  uint64
+	TotalOutputBytes uint64
+	NumInputRecords  uint64
+	NumOutputRecords uint64
+	ElapsedMicros    uint64
+}
+
+// WriteStallCondition describes whether writes are stalled.
+type WriteStallCondition int
+
+// Write stall conditions.
+const (
+	WriteStallConditionNormal  = WriteStallCondition(0)
+	WriteStallConditionDelayed = WriteStallCondition(1)
+	WriteStallConditionStopped = WriteStallCondition(2)
+)
+
+// WriteStallInfo describes a change of the write stall condition.
+type WriteStallInfo struct {
+	ColumnFamilyName string
+	Current          WriteStallCondition
+	Previous         WriteStallCondition
+}
+
+// BackgroundErrorReason describes the job that caused a background error.
+type BackgroundErrorReason int
+
+// Background error reasons.
+const (
+	BackgroundErrorReasonFlush         = BackgroundErrorReason(0)
+	BackgroundErrorReasonCompaction    = BackgroundErrorReason(1)
+	BackgroundErrorReasonWriteCallback = BackgroundErrorReason(2)
+	BackgroundErrorReasonMemTable      = BackgroundErrorReason(3)
+	BackgroundErrorReasonOther         = BackgroundErrorReason(4)
+)
+
+// TableFileCreationReason describes why a SST file was created.
+type TableFileCreationReason int
+
+// Table file creation reasons.
+const (
+	TableFileCreationReasonFlush      = TableFileCreationReason(0)
+	TableFileCreationReasonCompaction = TableFileCreationReason(1)
+	TableFileCreationReasonRecovery   = TableFileCreationReason(2)
+	TableFileCreationReasonMisc       = TableFileCreationReason(3)
+)
+
+// TableFileCreationInfo describes a created SST file.
+type TableFileCreationInfo struct {
+	DBName           string
+	ColumnFamilyName string
+	FilePath         string
+	JobID            int
+	Reason           TableFileCreationReason
+	FileSize         uint64
+	NumEntries       uint64
+	// Err is non-nil if the file could not be created.
+	Err error
+}
+
+// TableFileDeletionInfo describes a deleted SST file.
+type TableFileDeletionInfo struct {
+	DBName   string
+	FilePath string
+	JobID    int
+	// Err is non-nil if the file could not be deleted.
+	Err error
+}
+
+// AddEventListener registers an EventListener which is notified about
+// background events of databases opened with these options.
+func (opts *Options) AddEventListener(l EventListener) {
+	idx := registerEventListener(l)
+	C.gorocksdb_options_add_eventlistener(opts.c, C.uintptr_t(idx))
+}
+
+// Hold references to event listeners.
+var eventListeners = NewCOWList()
+
+func registerEventListener(l EventListener) int {
+	return eventListeners.Append(l)
+}
+
+func eventListenerError(cErr *C.char) error {
+	if cErr == nil {
+		return nil
+	}
+	return newError(C.GoString(cErr))
+}
+
+func eventListenerStrings(cStrs **C.char, cLens *C.size_t, cNum C.int) []string {
+	rawStrs := charSlice(cStrs, cNum)
+	strsLen := sizeSlice(cLens, cNum)
+	strs := make([]string, int(cNum))
+	for i, len := range strsLen {
+		strs[i] = C.GoStringN(rawStrs[i], C.int(len))
+	}
+	return strs
+}
+
+//export gorocksdb_eventlistener_on_flush_completed
+func gorocksdb_eventlistener_on_flush_completed(idx int, cCFName *C.char, cCFNameLen C.size_t, cFilePath *C.char, cFilePathLen C.size_t, cJobID C.int, cSlowdown C.uchar, cStop C.uchar, cSmallestSeqno C.uint64_t, cLargestSeqno C.uint64_t, cNumEntries C.uint64_t) {
+	eventListeners.Get(idx).(EventListener).OnFlushCompleted(FlushJobInfo{
+		ColumnFamilyName:        C.GoStringN(cCFName, C.int(cCFNameLen)),
+		FilePath:                C.GoStringN(cFilePath, C.int(cFilePathLen)),
+		JobID:                   int(cJobID),
+		TriggeredWritesSlowdown: cSlowdown != 0,
+		TriggeredWritesStop:     cStop != 0,
+		SmallestSeqno:           uint64(cSmallestSeqno),
+		LargestSeqno:            uint64(cLargestSeqno),
+		NumEntries:              uint64(cNumEntries),
+	})
+}
+
+//export gorocksdb_eventlistener_on_compaction_completed
+func gorocksdb_eventlistener_on_compaction_completed(idx int, cCFName *C.char, cCFNameLen C.size_t, cErr *C.char, cJobID C.int, cBaseInputLevel C.int, cOutputLevel C.int, cInputFiles **C.char, cInputFileLens *C.size_t, cNumInputFiles C.int, cOutputFiles **C.char, cOutputFileLens *C.size_t, cNumOutputFiles C.int, cReason C.int, cTotalInputBytes C.uint64_t, cTotalOutputBytes C.uint64_t, cNumInputRecords C.uint64_t, cNumOutputRecords C.uint64_t, cElapsedMicros C.uint64_t) {
+	eventListeners.Get(idx).(EventListener).OnCompactionCompleted(CompactionJobInfo{
+		ColumnFamilyName: C.GoStringN(cCFName, C.int(cCFNameLen)),
+		Err:              eventListenerError(cErr),
+		JobID:            int(cJobID),
+		BaseInputLevel:   int(cBaseInputLevel),
+		OutputLevel:      int(cOutputLevel),
+		InputFiles:       eventListenerStrings(cInputFiles, cInputFileLens, cNumInputFiles),
+		OutputFiles:      eventListenerStrings(cOutputFiles, cOutputFileLens, cNumOutputFiles),
+		Reason:           CompactionReason(cReason),
+		TotalInputBytes:  uint64(cTotalInputBytes),
+		TotalOutputBytes: uint64(cTotalOutputBytes),
+		NumInputRecords:  uint64(cNumInputRecords),
+		NumOutputRecords: uint64(cNumOutputRecords),
+		ElapsedMicros:    uint64(cElapsedMicros),
+	})
+}
+
+//export gorocksdb_eventlistener_on_stall_conditions_changed
+func gorocksdb_eventlistener_on_stall_conditions_changed(idx int, cCFName *C.char, cCFNameLen C.size_t, cCurrent C.int, cPrevious C.int) {
+	eventListeners.Get(idx).(EventListener).OnStallConditionsChanged(WriteStallInfo{
+		ColumnFamilyName: C.GoStringN(cCFName, C.int(cCFNameLen)),
+		Current:          WriteStallCondition(cCurrent),
+		Previous:         WriteStallCondition(cPrevious),
+	})
+}
+
+//export gorocksdb_eventlistener_on_background_error
+func gorocksdb_eventlistener_on_background_error(idx int, cReason C.int, cErr *C.char) {
+	eventListeners.Get(idx).(EventListener).OnBackgroundError(BackgroundErrorReason(cReason), eventListenerError(cErr))
+}
+
+//export gorocksdb_eventlistener_on_table_file_created
+func gorocksdb_eventlistener_on_table_file_created(idx int, cDBName *C.char, cDBNameLen C.size_t, cCFName *C.char, cCFNameLen C.size_t, cFilePath *C.char, cFilePathLen C.size_t, cJobID C.int, cReason C.int, cFileSize C.uint64_t, cNumEntries C.uint64_t, cErr *C.char) {
+	eventListeners.Get(idx).(EventListener).OnTableFileCreated(TableFileCreationInfo{
+		DBName:           C.GoStringN(cDBName, C.int(cDBNameLen)),
+		ColumnFamilyName: C.GoStringN(cCFName, C.int(cCFNameLen)),
+		FilePath:         C.GoStringN(cFilePath, C.int(cFilePathLen)),
+		JobID:            int(cJobID),
+		Reason:           TableFileCreationReason(cReason),
+		FileSize:         uint64(cFileSize),
+		NumEntries:       uint64(cNumEntries),
+		Err:              eventListenerError(cErr),
+	})
+}
+
+//export gorocksdb_eventlistener_on_table_file_deleted
+func gorocksdb_eventlistener_on_table_file_deleted(idx int, cDBName *C.char, cDBNameLen C.size_t, cFilePath *C.char, cFilePathLen C.size_t, cJobID C.int, cErr *C.char) {
+	eventListeners.Get(idx).(EventListener).OnTableFileDeleted(TableFileDeletionInfo{
+		DBName:   C.GoStringN(cDBName, C.int(cDBNameLen)),
+		FilePath: C.GoStringN(cFilePath, C.int(cFilePathLen)),
+		JobID:    int(cJobID),
+		Err:      eventListenerError(cErr),
+	})
+}
@@ -0,0 +1,89 @@
+package gorocksdb
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestEventListener(t *testing.T) {
+	listener := &mockEventListener{}
+	db := newTestDB(t, "TestEventListener", func(opts *Options) {
+		opts.AddEventListener(listener)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	fo := NewDefaultFlushOptions()
+	fo.SetWait(true)
+	defer fo.Destroy()
+
+	// create two level 0 files
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val")))
+	ensure.Nil(t, db.Flush(fo))
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("val")))
+	ensure.Nil(t, db.Flush(fo))
+
+	// compact them into one file
+	db.CompactRange(Range{nil, nil})
+
+	flushes, compactions, created := listener.events()
+
+	ensure.DeepEqual(t, len(flushes), 2)
+	ensure.DeepEqual(t, flushes[0].ColumnFamilyName, "default")
+	ensure.DeepEqual(t, flushes[0].NumEntries, uint64(1))
+
+	ensure.True(t, len(compactions) > 0)
+	compaction := compactions[len(compactions)-1]
+	ensure.Nil(t, compaction.Err)
+	ensure.DeepEqual(t, len(compaction.InputFiles), 2)
+	ensure.DeepEqual(t, len(compaction.OutputFiles), 1)
+	ensure.DeepEqual(t, compaction.Reason, CompactionReasonManualCompaction)
+	ensure.DeepEqual(t, compaction.NumInputRecords, uint64(2))
+
+	ensure.True(t, len(created) >= 3)
+	ensure.Nil(t, created[0].Err)
+	ensure.DeepEqual(t, created[0].Reason, TableFileCreationReasonFlush)
+}
+
+type mockEventListener struct {
+	mu          sync.Mutex
+	flushes     []FlushJobInfo
+	compactions []CompactionJobInfo
+	created     []TableFileCreationInfo
+	deleted     []TableFileDeletionInfo
+}
+
+// events returns copies of the events received so far. The listener is
+// called from the background threads of the database.
+func (m *mockEventListener) events() ([]FlushJobInfo, []CompactionJobInfo, []TableFileCreationInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]FlushJobInfo(nil), m.flushes...),
+		append([]CompactionJobInfo(nil), m.compactions...),
+		append([]TableFileCreationInfo(nil), m.created...)
+}
+
+func (m *mockEventListener) OnFlushCompleted(info FlushJobInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flushes = append(m.flushes, info)
+}
+func (m *mockEventListener) OnCompactionCompleted(info CompactionJobInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.compactions = append(m.compactions, info)
+}
+func (m *mockEventListener) OnStallConditionsChanged(info WriteStallInfo)              {}
+func (m *mockEventListener) OnBackgroundError(reason BackgroundErrorReason, err error) {}
+func (m *mockEventListener) OnTableFileCreated(info TableFileCreationInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.created = append(m.created, info)
+}
+func (m *mockEventListener) OnTableFileDeleted(info TableFileDeletionInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, info)
+}
@@ -7,19 +7,59 @@
 
 extern void gorocksdb_destruct_handler(void* state);
 
+/* Status */
+
+// The status of an operation, msg is allocated with malloc and null if the
+// operation succeeded.
+typedef struct {
+    int code;
+    int subcode;
+    char* msg;
+} gorocksdb_status_t;
+
+/* DB */
+
+extern void gorocksdb_multi_get(rocksdb_t* db, const rocksdb_readoptions_t* opts, rocksdb_column_family_handle_t* const* column_families, size_t num_keys, const char* const* keys, const size_t* key_sizes, char** values, size_t* value_sizes, gorocksdb_status_t* statuses);
+
+/* Backup Engine */
+
+extern void gorocksdb_backup_engine_create_new_backup_with_metadata(rocksdb_backup_engine_t* be, rocksdb_t* db, const char* app_metadata, size_t app_metadata_len, unsigned char flush_before_backup, char** errptr);
+extern void gorocksdb_backup_engine_create_new_backup_transactiondb(rocksdb_backup_engine_t* be, rocksdb_transactiondb_t* db, const char* app_metadata, size_t app_metadata_len, unsigned char flush_before_backup, char** errptr);
+extern void gorocksdb_backup_engine_stop_backup(rocksdb_backup_engine_t* be);
+extern void gorocksdb_backup_engine_delete_backup(rocksdb_backup_engine_t* be, uint32_t backup_id, char** errptr);
+extern char* gorocksdb_backup_engine_get_app_metadata(rocksdb_backup_engine_t* be, uint32_t backup_id, size_t* len, char** errptr);
+
 /* CompactionFilter */
 
 extern rocksdb_compactionfilter_t* gorocksdb_compactionfilter_create(uintptr_t idx);
 
+typedef struct gorocksdb_compactionfilter_t gorocksdb_compactionfilter_t;
+
+extern gorocksdb_compactionfilter_t* gorocksdb_compactionfilter_create_v2(uintptr_t idx);
+extern void gorocksdb_compactionfilter_destroy(gorocksdb_compactionfilter_t* filter);
+extern void gorocksdb_options_set_compactionfilter(rocksdb_options_t* opts, gorocksdb_compactionfilter_t* filter);
+
+/* CompactionFilterFactory */
+
+extern void gorocksdb_options_set_compactionfilterfactory(rocksdb_options_t* opts, uintptr_t idx);
+
 /* Comparator */
 
 extern rocksdb_comparator_t* gorocksdb_comparator_create(uintptr_t idx);
 
+/* Event Listener */
+
+extern void gorocksdb_options_add_eventlistener(rocksdb_options_t* opts, uintptr_t idx);
+
 /* Filter Policy */
 
 extern rocksdb_filterpolicy_t* gorocksdb_filterpolicy_create(uintptr_t idx);
 extern void gorocksdb_filterpolicy_delete_filter(void* state, const char* v, size_t s);
 
+/* Logger */
+
+extern void gorocksdb_options_set_logger(rocksdb_options_t* opts, uintptr_t idx, int level);
+
 /* Merge Operator */
 
 extern rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create(uintptr_t idx);
@@ -28,3 +68,173 @@ extern void gorocksdb_mergeoperator_delete_value(void* state, const char* v, siz
 /* Slice Transform */
 
 extern rocksdb_slicetransform_t* gorocksdb_slicetransform_create(uintptr_t idx);
+
+/* SST File Writer */
+
+// Strings are allocated with malloc and released by gorocksdb_externalsstfileinfo_destroy.
+typedef struct {
+    char* file_path;
+    char* smallest_key;
+    size_t smallest_key_len;
+    char* largest_key;
+    size_t largest_key_len;
+    char* smallest_range_del_key;
+    size_t smallest_range_del_key_len;
+    char* largest_range_del_key;
+    size_t largest_range_del_key_len;
+    uint64_t sequence_number;
+    uint64_t file_size;
+    uint64_t num_entries;
+    uint64_t num_range_del_entries;
+    int32_t version;
+} gorocksdb_externalsstfileinfo_t;
+
+extern void gorocksdb_sstfilewriter_finish(rocksdb_sstfilewriter_t* writer, gorocksdb_externalsstfileinfo_t* info, char** errptr);
+extern void gorocksdb_externalsstfileinfo_destroy(gorocksdb_externalsstfileinfo_t* info);
+
+/* Table Properties */
+
+// Strings are allocated with malloc and released by gorocksdb_tableproperties_destroy.
+typedef struct {
+    uint64_t data_size;
+    uint64_t index_size;
+    uint64_t filter_size;
+    uint64_t raw_key_size;
+    uint64_t raw_value_size;
+    uint64_t num_data_blocks;
+    uint64_t num_entries;
+    uint64_t num_deletions;
+    uint64_t num_merge_operands;
+    uint64_t num_range_deletions;
+    uint64_t format_version;
+    uint64_t creation_time;
+    uint64_t oldest_key_time;
+    uint64_t file_creation_time;
+    char* column_family_name;
+    char* comparator_name;
+    char* merge_operator_name;
+    char* compression_name;
+    size_t num_user_collected_properties;
+    char** user_collected_property_keys;
+    size_t* user_collected_property_key_lens;
+    char** user_collected_property_values;
+    size_t* user_collected_property_value_lens;
+} gorocksdb_tableproperties_t;
+
+extern void gorocksdb_tableproperties_destroy(gorocksdb_tableproperties_t* props);
+
+typedef struct gorocksdb_tablepropertiescollection_t gorocksdb_tablepropertiescollection_t;
+
+extern gorocksdb_tablepropertiescollection_t* gorocksdb_get_properties_of_all_tables(rocksdb_t* db, rocksdb_column_family_handle_t* column_family, char** errptr);
+extern gorocksdb_tablepropertiescollection_t* gorocksdb_get_properties_of_tables_in_range(rocksdb_t* db, rocksdb_column_family_handle_t* column_family, size_t num_ranges, const char* const* start_keys, const size_t* start_key_lens, const char* const* limit_keys, const size_t* limit_key_lens, char** errptr);
+extern size_t gorocksdb_tablepropertiescollection_count(const gorocksdb_tablepropertiescollection_t* coll);
+extern const char* gorocksdb_tablepropertiescollection_name(const gorocksdb_tablepropertiescollection_t* coll, size_t index);
+extern void gorocksdb_tablepropertiescollection_get(const gorocksdb_tablepropertiescollection_t* coll, size_t index, gorocksdb_tableproperties_t* props);
+extern void gorocksdb_tablepropertiescollection_destroy(gorocksdb_tablepropertiescollection_t* coll);
+
+/* SST File Reader */
+
+typedef struct gorocksdb_sstfilereader_t gorocksdb_sstfilereader_t;
+
+extern gorocksdb_sstfilereader_t* gorocksdb_sstfilereader_create(const rocksdb_options_t* opts);
+extern void gorocksdb_sstfilereader_open(gorocksdb_sstfilereader_t* reader, const char* path, char** errptr);
+extern rocksdb_iterator_t* gorocksdb_sstfilereader_new_iterator(gorocksdb_sstfilereader_t* reader, const rocksdb_readoptions_t* opts);
+extern void gorocksdb_sstfilereader_verify_checksum(gorocksdb_sstfilereader_t* reader, char** errptr);
+extern void gorocksdb_sstfilereader_get_table_properties(gorocksdb_sstfilereader_t* reader, gorocksdb_tableproperties_t* props);
+extern void gorocksdb_sstfilereader_destroy(gorocksdb_sstfilereader_t* reader);
+
+/* Read Options */
+
+extern rocksdb_readoptions_t* gorocksdb_readoptions_copy(const rocksdb_readoptions_t* opts);
+extern void gorocksdb_readoptions_set_auto_prefix_mode(rocksdb_readoptions_t* opts, unsigned char v);
+extern void gorocksdb_readoptions_set_table_filter(rocksdb_readoptions_t* opts, uintptr_t idx, unsigned char enabled);
+
+/* Transaction */
+
+extern void gorocksdb_transaction_singledelete(rocksdb_transaction_t* txn, const char* key, size_t klen, char** errptr);
+extern void gorocksdb_transaction_singledelete_cf(rocksdb_transaction_t* txn, rocksdb_column_family_handle_t* column_family, const char* key, size_t klen, char** errptr);
+extern void gorocksdb_transaction_free_handle(rocksdb_transaction_t* txn);
+
+/* Compaction */
+
+extern void gorocksdb_compactoptions_set_allow_write_stall(rocksdb_compactoptions_t* opts, unsigned char v);
+extern void gorocksdb_compactoptions_set_max_subcompactions(rocksdb_compactoptions_t* opts, uint32_t v);
+typedef struct gorocksdb_cancel_flag_t gorocksdb_cancel_flag_t;
+
+extern gorocksdb_cancel_flag_t* gorocksdb_cancel_flag_create();
+extern void gorocksdb_cancel_flag_set(gorocksdb_cancel_flag_t* flag);
+extern void gorocksdb_cancel_flag_destroy(gorocksdb_cancel_flag_t* flag);
+extern void gorocksdb_compact_range(rocksdb_t* db, rocksdb_column_family_handle_t* column_family, const rocksdb_compactoptions_t* opts, const char* start_key, size_t start_key_len, const char* limit_key, size_t limit_key_len, gorocksdb_cancel_flag_t* canceled, char** errptr);
+extern void gorocksdb_compact_files(rocksdb_t* db, rocksdb_column_family_handle_t* column_family, const char* const* names, size_t num_names, int output_level, char** errptr);
+
+/* Metadata */
+
+// Strings are allocated with malloc and released by gorocksdb_sstfilemetadata_destroy.
+typedef struct {
+    char* name;
+    uint64_t size;
+    char* smallest_key;
+    size_t smallest_key_len;
+    char* largest_key;
+    size_t largest_key_len;
+    uint64_t smallest_seqno;
+    uint64_t largest_seqno;
+    uint64_t num_entries;
+    uint64_t num_deletions;
+    unsigned char being_compacted;
+    uint64_t file_creation_time;
+} gorocksdb_sstfilemetadata_t;
+
+extern void gorocksdb_sstfilemetadata_destroy(gorocksdb_sstfilemetadata_t* meta);
+
+typedef struct gorocksdb_livefiles_t gorocksdb_livefiles_t;
+
+extern gorocksdb_livefiles_t* gorocksdb_livefiles(rocksdb_t* db);
+extern size_t gorocksdb_livefiles_count(const gorocksdb_livefiles_t* lf);
+extern void gorocksdb_livefiles_metadata(const gorocksdb_livefiles_t* lf, size_t index, const char** column_family_name, int* level, gorocksdb_sstfilemetadata_t* meta);
+extern void gorocksdb_livefiles_destroy(gorocksdb_livefiles_t* lf);
+
+typedef struct gorocksdb_column_family_metadata_t gorocksdb_column_family_metadata_t;
+
+extern gorocksdb_column_family_metadata_t* gorocksdb_get_column_family_metadata(rocksdb_t* db, rocksdb_column_family_handle_t* column_family);
+extern const char* gorocksdb_column_family_metadata_name(const gorocksdb_column_family_metadata_t* cf_meta);
+extern uint64_t gorocksdb_column_family_metadata_size(const gorocksdb_column_family_metadata_t* cf_meta);
+extern size_t gorocksdb_column_family_metadata_file_count(const gorocksdb_column_family_metadata_t* cf_meta);
+extern size_t gorocksdb_column_family_metadata_level_count(const gorocksdb_column_family_metadata_t* cf_meta);
+extern void gorocksdb_column_family_metadata_level(const gorocksdb_column_family_metadata_t* cf_meta, size_t index, int* level, uint64_t* size, size_t* file_count);
+extern void gorocksdb_column_family_metadata_file(const gorocksdb_column_family_metadata_t* cf_meta, size_t level_index, size_t file_index, gorocksdb_sstfilemetadata_t* meta);
+extern void gorocksdb_column_family_metadata_destroy(gorocksdb_column_family_metadata_t* cf_meta);
+
+/* Table Properties Collector */
+
+extern void gorocksdb_options_add_tablepropertiescollectorfactory(rocksdb_options_t* opts, uintptr_t idx);
+extern void gorocksdb_usercollectedproperties_add(void* properties, const char* key, size_t klen, const char* val, size_t vlen);
+
+/* Env */
+
+#define GOROCKSDB_ENV_HOOK_OPEN 1
+#define GOROCKSDB_ENV_HOOK_READ 2
+#define GOROCKSDB_ENV_HOOK_WRITE 4
+#define GOROCKSDB_ENV_HOOK_SYNC 8
+#define GOROCKSDB_ENV_HOOK_RENAME 16
+#define GOROCKSDB_ENV_HOOK_DELETE 32
+
+#define GOROCKSDB_FILE_OPEN_SEQUENTIAL 0
+#define GOROCKSDB_FILE_OPEN_RANDOM_ACCESS 1
+#define GOROCKSDB_FILE_OPEN_WRITABLE 2
+
+typedef struct gorocksdb_env_t gorocksdb_env_t;
+
+extern gorocksdb_env_t* gorocksdb_create_hooked_env(uintptr_t idx, unsigned char mask);
+extern void gorocksdb_env_set_background_threads(gorocksdb_env_t* env, int n);
+extern void gorocksdb_env_set_high_priority_background_threads(gorocksdb_env_t* env, int n);
+extern void gorocksdb_env_destroy(gorocksdb_env_t* env);
+extern void gorocksdb_options_set_env(rocksdb_options_t* opts, gorocksdb_env_t* env);
+
+/* Statistics */
+
+extern uint32_t gorocksdb_ticker_type(int ticker);
+extern uint32_t gorocksdb_histogram_type(int histogram);
+extern const char* gorocksdb_ticker_name(int ticker);
+extern const char* gorocksdb_histogram_name(int histogram);
+extern void gorocksdb_options_statistics_reset(rocksdb_options_t* opts, char** errptr);
@@ -0,0 +1,1281 @@
+// This file provides the C wrapper functions for the parts of rocksdb that
+// are not exposed by the rocksdb C API.
+
+#include <atomic>
+#include <cstdarg>
+#include <cstdio>
+#include <cstdlib>
+#include <cstring>
+#include <memory>
+#include <string>
+#include <vector>
+
+#include "rocksdb/compaction_filter.h"
+#include "rocksdb/env.h"
+#include "rocksdb/file_system.h"
+#include "rocksdb/listener.h"
+#include "rocksdb/options.h"
+#include "rocksdb/sst_file_reader.h"
+#include "rocksdb/sst_file_writer.h"
+#include "rocksdb/statistics.h"
+#include "rocksdb/table_properties.h"
+#include "rocksdb/utilities/backup_engine.h"
+#include "rocksdb/utilities/transaction_db.h"
+#include "rocksdb/version.h"
+
+// The shims depend on the layout of the C API handles, see gorocksdb_rep.
+// Check the layout against a new rocksdb release before extending the range.
+#if ROCKSDB_MAJOR < 8 || ROCKSDB_MAJOR > 9
+#error "gorocksdb requires rocksdb 8.x or 9.x"
+#endif
+
+extern "C" {
+#include "gorocksdb.h"
+#include "_cgo_export.h"
+}
+
+using namespace rocksdb;
+
+// The handles of the rocksdb C API are defined in rocksdb's c.cc and are
+// opaque to the public headers. Each handle read here wraps its object as the
+// first member, which holds for the rocksdb versions checked above. The
+// handles are only read through gorocksdb_rep and the upstream structs are
+// never redeclared. This file uses its own handles instead of creating
+// upstream ones, except for the iterators of SST file readers.
+template <typename T>
+struct gorocksdb_handle {
+  T rep;
+};
+
+template <typename T, typename H>
+static T& gorocksdb_rep(H* handle) {
+  return reinterpret_cast<gorocksdb_handle<T>*>(handle)->rep;
+}
+
+template <typename T, typename H>
+static const T& gorocksdb_rep(const H* handle) {
+  return reinterpret_cast<const gorocksdb_handle<T>*>(handle)->rep;
+}
+
+// Returns the column family of the handle, or the default column family of db
+// if the handle is null.
+static ColumnFamilyHandle* gorocksdb_column_family(rocksdb_t* db, rocksdb_column_family_handle_t* column_family) {
+  if (column_family == nullptr) {
+    return gorocksdb_rep<DB*>(db)->DefaultColumnFamily();
+  }
+  return gorocksdb_rep<ColumnFamilyHandle*>(column_family);
+}
+
+// Stores a non-ok status in errptr the same way rocksdb's c.cc does, so the
+// message can be released with rocksdb_free.
+static void gorocksdb_save_error(char** errptr, const Status& s) {
+  if (s.ok()) {
+    return;
+  }
+  if (*errptr != nullptr) {
+    free(*errptr);
+  }
+  *errptr = strdup(s.ToString().c_str());
+}
+
+// Stores the code, subcode and message of s in status, the message is
+// allocated with malloc and null if s is ok.
+static void gorocksdb_save_status(gorocksdb_status_t* status, const Status& s) {
+  status->code = s.code();
+  status->subcode = s.subcode();
+  status->msg = s.ok() ? nullptr : strdup(s.ToString().c_str());
+}
+
+// Returns a malloc'ed copy of str, which may contain null bytes.
+static char* gorocksdb_copy_string(const std::string& str, size_t* len) {
+  *len = str.size();
+  char* result = static_cast<char*>(malloc(str.size() + 1));
+  memcpy(result, str.data(), str.size());
+  result[str.size()] = '\0';
+  return result;
+}
+
+/* DB */
+
+void gorocksdb_multi_get(rocksdb_t* db, const rocksdb_readoptions_t* opts,
+                         rocksdb_column_family_handle_t* const* column_families, size_t num_keys,
+                         const char* const* keys, const size_t* key_sizes, char** values, size_t* value_sizes,
+                         gorocksdb_status_t* statuses) {
+  std::vector<ColumnFamilyHandle*> cfs(num_keys);
+  std::vector<Slice> key_slices(num_keys);
+  for (size_t i = 0; i < num_keys; i++) {
+    cfs[i] = gorocksdb_column_family(db, column_families != nullptr ? column_families[i] : nullptr);
+    key_slices[i] = Slice(keys[i], key_sizes[i]);
+  }
+  std::vector<std::string> vals;
+  std::vector<Status> s = gorocksdb_rep<DB*>(db)->MultiGet(gorocksdb_rep<ReadOptions>(opts), cfs, key_slices, &vals);
+  for (size_t i = 0; i < num_keys; i++) {
+    values[i] = nullptr;
+    value_sizes[i] = 0;
+    if (s[i].ok()) {
+      values[i] = gorocksdb_copy_string(vals[i], &value_sizes[i]);
+    }
+    // like rocksdb_multi_get, a missing key is not an error
+    gorocksdb_save_status(&statuses[i], s[i].IsNotFound() ? Status::OK() : s[i]);
+  }
+}
+
+/* Backup Engine */
+
+void gorocksdb_backup_engine_create_new_backup_with_metadata(rocksdb_backup_engine_t* be, rocksdb_t* db, const char* app_metadata, size_t app_metadata_len, unsigned char flush_before_backup, char** errptr) {
+  gorocksdb_save_error(errptr, gorocksdb_rep<BackupEngine*>(be)->CreateNewBackupWithMetadata(
+      gorocksdb_rep<DB*>(db), std::string(app_metadata, app_metadata_len), flush_before_backup));
+}
+
+void gorocksdb_backup_engine_create_new_backup_transactiondb(rocksdb_backup_engine_t* be, rocksdb_transactiondb_t* db, const char* app_metadata, size_t app_metadata_len, unsigned char flush_before_backup, char** errptr) {
+  gorocksdb_save_error(errptr, gorocksdb_rep<BackupEngine*>(be)->CreateNewBackupWithMetadata(
+      gorocksdb_rep<DB*>(db), std::string(app_metadata, app_metadata_len), flush_before_backup));
+}
+
+void gorocksdb_backup_engine_stop_backup(rocksdb_backup_engine_t* be) {
+  gorocksdb_rep<BackupEngine*>(be)->StopBackup();
+}
+
+void gorocksdb_backup_engine_delete_backup(rocksdb_backup_engine_t* be, uint32_t backup_id, char** errptr) {
+  gorocksdb_save_error(errptr, gorocksdb_rep<BackupEngine*>(be)->DeleteBackup(backup_id));
+}
+
+char* gorocksdb_backup_engine_get_app_metadata(rocksdb_backup_engine_t* be, uint32_t backup_id, size_t* len, char** errptr) {
+  BackupInfo info;
+  Status s = gorocksdb_rep<BackupEngine*>(be)->GetBackupInfo(backup_id, &info);
+  if (!s.ok()) {
+    gorocksdb_save_error(errptr, s);
+    return nullptr;
+  }
+  return gorocksdb_copy_string(info.app_metadata, len);
+}
+
+/* CompactionFilter */
+
+// Values must match the CompactionValueType constants in compaction_filter.go.
+static int gorocksdb_compaction_value_type(CompactionFilter::ValueType type) {
+  switch (type) {
+    case CompactionFilter::ValueType::kValue:
+      return 0;
+    case CompactionFilter::ValueType::kMergeOperand:
+      return 1;
+    case CompactionFilter::ValueType::kBlobIndex:
+      return 2;
+    default:
+      return -1;
+  }
+}
+
+// Values must match the CompactionDecision constants in compaction_filter.go.
+static CompactionFilter::Decision gorocksdb_compaction_decision(int decision) {
+  switch (decision) {
+    case 1:
+      return CompactionFilter::Decision::kRemove;
+    case 2:
+      return CompactionFilter::Decision::kChangeValue;
+    case 3:
+      return CompactionFilter::Decision::kRemoveAndSkipUntil;
+    default:
+      return CompactionFilter::Decision::kKeep;
+  }
+}
+
+// A compaction filter which calls back into go. It is either registered
+// directly through gorocksdb_options_set_compactionfilter or created per
+// compaction job by GoCompactionFilterFactory, in which case the go side
+// releases the job's filter once this object is destroyed.
+struct gorocksdb_compactionfilter_t : public CompactionFilter {
+  uintptr_t idx_;
+  bool job_;
+
+  gorocksdb_compactionfilter_t(uintptr_t idx, bool job) : idx_(idx), job_(job) {}
+
+  ~gorocksdb_compactionfilter_t() override {
+    if (job_) {
+      gorocksdb_compactionfilterfactory_destroy_filter(idx_);
+    }
+  }
+
+  Decision FilterV2(int level, const Slice& key, ValueType value_type,
+                    const Slice& existing_value, std::string* new_value,
+                    std::string* skip_until) const override {
+    int c_value_type = gorocksdb_compaction_value_type(value_type);
+    if (c_value_type < 0) {
+      return Decision::kKeep;
+    }
+    char* c_new_value = nullptr;
+    size_t new_value_len = 0;
+    char* c_skip_until = nullptr;
+    size_t skip_until_len = 0;
+    Decision decision = gorocksdb_compaction_decision(gorocksdb_compactionfilter_filter_v2(
+        idx_, job_, level,
+        const_cast<char*>(key.data()), key.size(),
+        c_value_type,
+        const_cast<char*>(existing_value.data()), existing_value.size(),
+        &c_new_value, &new_value_len,
+        &c_skip_until, &skip_until_len));
+    if (decision == Decision::kChangeValue) {
+      new_value->assign(c_new_value, new_value_len);
+    } else if (decision == Decision::kRemoveAndSkipUntil) {
+      skip_until->assign(c_skip_until, skip_until_len);
+    }
+    return decision;
+  }
+
+  const char* Name() const override {
+    if (job_) {
+      return gorocksdb_compactionfilterfactory_filter_name(idx_);
+    }
+    return gorocksdb_compactionfilter_name(idx_);
+  }
+};
+
+gorocksdb_compactionfilter_t* gorocksdb_compactionfilter_create_v2(uintptr_t idx) {
+  return new gorocksdb_compactionfilter_t(idx, false);
+}
+
+void gorocksdb_compactionfilter_destroy(gorocksdb_compactionfilter_t* filter) {
+  delete filter;
+}
+
+void gorocksdb_options_set_compactionfilter(rocksdb_options_t* opts, gorocksdb_compactionfilter_t* filter) {
+  gorocksdb_rep<Options>(opts).compaction_filter = filter;
+}
+
+/* CompactionFilterFactory */
+
+class GoCompactionFilterFactory : public CompactionFilterFactory {
+ public:
+  explicit GoCompactionFilterFactory(uintptr_t idx) : idx_(idx) {}
+
+  std::unique_ptr<CompactionFilter> CreateCompactionFilter(
+      const CompactionFilter::Context& context) override {
+    uintptr_t filter_idx = gorocksdb_compactionfilterfactory_create_filter(
+        idx_,
+        context.is_full_compaction,
+        context.is_manual_compaction,
+        context.column_family_id);
+    if (filter_idx == 0) {
+      return nullptr;
+    }
+    return std::unique_ptr<CompactionFilter>(new gorocksdb_compactionfilter_t(filter_idx, true));
+  }
+
+  const char* Name() const override {
+    return gorocksdb_compactionfilterfactory_name(idx_);
+  }
+
+ private:
+  uintptr_t idx_;
+};
+
+void gorocksdb_options_set_compactionfilterfactory(rocksdb_options_t* opts, uintptr_t idx) {
+  gorocksdb_rep<Options>(opts).compaction_filter_factory = std::make_shared<GoCompactionFilterFactory>(idx);
+}
+
+/* Event Listener */
+
+static int gorocksdb_compaction_reason(CompactionReason reason) {
+  switch (reason) {
+    case CompactionReason::kLevelL0FilesNum: return 1;
+    case CompactionReason::kLevelMaxLevelSize: return 2;
+    case CompactionReason::kUniversalSizeAmplification: return 3;
+    case CompactionReason::kUniversalSizeRatio: return 4;
+    case CompactionReason::kUniversalSortedRunNum: return 5;
+    case CompactionReason::kFIFOMaxSize: return 6;
+    case CompactionReason::kFIFOReduceNumFiles: return 7;
+    case CompactionReason::kFIFOTtl: return 8;
+    case CompactionReason::kManualCompaction: return 9;
+    case CompactionReason::kFilesMarkedForCompaction: return 10;
+    case CompactionReason::kBottommostFiles: return 11;
+    case CompactionReason::kTtl: return 12;
+    case CompactionReason::kFlush: return 13;
+    case CompactionReason::kExternalSstIngestion: return 14;
+    default: return 0;
+  }
+}
+
+static int gorocksdb_write_stall_condition(WriteStallCondition condition) {
+  switch (condition) {
+    case WriteStallCondition::kDelayed: return 1;
+    case WriteStallCondition::kStopped: return 2;
+    default: return 0;
+  }
+}
+
+static int gorocksdb_background_error_reason(BackgroundErrorReason reason) {
+  switch (reason) {
+    case BackgroundErrorReason::kFlush: return 0;
+    case BackgroundErrorReason::kCompaction: return 1;
+    case BackgroundErrorReason::kWriteCallback: return 2;
+    case BackgroundErrorReason::kMemTable: return 3;
+    default: return 4;
+  }
+}
+
+static int gorocksdb_table_file_creation_reason(TableFileCreationReason reason) {
+  switch (reason) {
+    case TableFileCreationReason::kFlush: return 0;
+    case TableFileCreationReason::kCompaction: return 1;
+    case TableFileCreationReason::kRecovery: return 2;
+    default: return 3;
+  }
+}
+
+// Returns the message of a non-ok status, nullptr otherwise. The returned
+// string is only valid as long as msg.
+static char* gorocksdb_status_message(const Status& s, std::string* msg) {
+  if (s.ok()) {
+    return nullptr;
+  }
+  *msg = s.ToString();
+  return const_cast<char*>(msg->c_str());
+}
+
+class GoEventListener : public EventListener {
+ public:
+  explicit GoEventListener(uintptr_t idx) : idx_(idx) {}
+
+  void OnFlushCompleted(DB* db, const FlushJobInfo& info) override {
+    gorocksdb_eventlistener_on_flush_completed(
+        idx_,
+        const_cast<char*>(info.cf_name.data()), info.cf_name.size(),
+        const_cast<char*>(info.file_path.data()), info.file_path.size(),
+        info.job_id,
+        info.triggered_writes_slowdown,
+        info.triggered_writes_stop,
+        info.smallest_seqno,
+        info.largest_seqno,
+        info.table_properties.num_entries);
+  }
+
+  void OnCompactionCompleted(DB* db, const CompactionJobInfo& info) override {
+    std::vector<char*> inputs, outputs;
+    std::vector<size_t> input_lens, output_lens;
+    for (const auto& f : info.input_files) {
+      inputs.push_back(const_cast<char*>(f.data()));
+      input_lens.push_back(f.size());
+    }
+    for (const auto& f : info.output_files) {
+      outputs.push_back(const_cast<char*>(f.data()));
+      output_lens.push_back(f.size());
+    }
+    std::string msg;
+    gorocksdb_eventlistener_on_compaction_completed(
+        idx_,
+        const_cast<char*>(info.cf_name.data()), info.cf_name.size(),
+        gorocksdb_status_message(info.status, &msg),
+        info.job_id,
+        info.base_input_level,
+        info.output_level,
+        inputs.data(), input_lens.data(), static_cast<int>(inputs.size()),
+        outputs.data(), output_lens.data(), static_cast<int>(outputs.size()),
+        gorocksdb_compaction_reason(info.compaction_reason),
+        info.stats.total_input_bytes,
+        info.stats.total_output_bytes,
+        info.stats.num_input_records,
+        info.stats.num_output_records,
+        info.stats.elapsed_micros);
+  }
+
+  void OnStallConditionsChanged(const WriteStallInfo& info) override {
+    gorocksdb_eventlistener_on_stall_conditions_changed(
+        idx_,
+        const_cast<char*>(info.cf_name.data()), info.cf_name.size(),
+        gorocksdb_write_stall_condition(info.condition.cur),
+        gorocksdb_write_stall_condition(info.condition.prev));
+  }
+
+  void OnBackgroundError(BackgroundErrorReason reason, Status* bg_error) override {
+    std::string msg;
+    gorocksdb_eventlistener_on_background_error(
+        idx_,
+        gorocksdb_background_error_reason(reason),
+        gorocksdb_status_message(*bg_error, &msg));
+  }
+
+  void OnTableFileCreated(const TableFileCreationInfo& info) override {
+    std::string msg;
+    gorocksdb_eventlistener_on_table_file_created(
+        idx_,
+        const_cast<char*>(info.db_name.data()), info.db_name.size(),
+        const_cast<char*>(info.cf_name.data()), info.cf_name.size(),
+        const_cast<char*>(info.file_path.data()), info.file_path.size(),
+        info.job_id,
+        gorocksdb_table_file_creation_reason(info.reason),
+        info.file_size,
+        info.table_properties.num_entries,
+        gorocksdb_status_message(info.status, &msg));
+  }
+
+  void OnTableFileDeleted(const TableFileDeletionInfo& info) override {
+    std::string msg;
+    gorocksdb_eventlistener_on_table_file_deleted(
+        idx_,
+        const_cast<char*>(info.db_name.data()), info.db_name.size(),
+        const_cast<char*>(info.file_path.data()), info.file_path.size(),
+        info.job_id,
+        gorocksdb_status_message(info.status, &msg));
+  }
+
+ private:
+  uintptr_t idx_;
+};
+
+void gorocksdb_options_add_eventlistener(rocksdb_options_t* opts, uintptr_t idx) {
+  gorocksdb_rep<Options>(opts).listeners.emplace_back(new GoEventListener(idx));
+}
+
+/* Logger */
+
+class GoLogger : public Logger {
+ public:
+  GoLogger(uintptr_t idx, InfoLogLevel level) : Logger(level), idx_(idx) {}
+
+  using Logger::Logv;
+
+  void Logv(const char* format, va_list ap) override {
+    Logv(InfoLogLevel::INFO_LEVEL, format, ap);
+  }
+
+  void Logv(const InfoLogLevel level, const char* format, va_list ap) override {
+    if (level < GetInfoLogLevel()) {
+      return;
+    }
+    char buf[512];
+    va_list ap_copy;
+    va_copy(ap_copy, ap);
+    int n = vsnprintf(buf, sizeof(buf), format, ap_copy);
+    va_end(ap_copy);
+    if (n < 0) {
+      return;
+    }
+    if (static_cast<size_t>(n) < sizeof(buf)) {
+      gorocksdb_logger_log(idx_, static_cast<int>(level), buf, n);
+      return;
+    }
+    std::string msg(n + 1, '\0');
+    vsnprintf(&msg[0], msg.size(), format, ap);
+    gorocksdb_logger_log(idx_, static_cast<int>(level), &msg[0], n);
+  }
+
+ private:
+  uintptr_t idx_;
+};
+
+void gorocksdb_options_set_logger(rocksdb_options_t* opts, uintptr_t idx, int level) {
+  gorocksdb_rep<Options>(opts).info_log = std::make_shared<GoLogger>(idx, static_cast<InfoLogLevel>(level));
+}
+
+/* SST File Writer */
+
+void gorocksdb_sstfilewriter_finish(rocksdb_sstfilewriter_t* writer, gorocksdb_externalsstfileinfo_t* info, char** errptr) {
+  ExternalSstFileInfo rep;
+  Status s = gorocksdb_rep<SstFileWriter*>(writer)->Finish(&rep);
+  if (!s.ok()) {
+    gorocksdb_save_error(errptr, s);
+    return;
+  }
+  size_t file_path_len;
+  info->file_path = gorocksdb_copy_string(rep.file_path, &file_path_len);
+  info->smallest_key = gorocksdb_copy_string(rep.smallest_key, &info->smallest_key_len);
+  info->largest_key = gorocksdb_copy_string(rep.largest_key, &info->largest_key_len);
+  info->smallest_range_del_key = gorocksdb_copy_string(rep.smallest_range_del_key, &info->smallest_range_del_key_len);
+  info->largest_range_del_key = gorocksdb_copy_string(rep.largest_range_del_key, &info->largest_range_del_key_len);
+  info->sequence_number = rep.sequence_number;
+  info->file_size = rep.file_size;
+  info->num_entries = rep.num_entries;
+  info->num_range_del_entries = rep.num_range_del_entries;
+  info->version = rep.version;
+}
+
+void gorocksdb_externalsstfileinfo_destroy(gorocksdb_externalsstfileinfo_t* info) {
+  free(info->file_path);
+  free(info->smallest_key);
+  free(info->largest_key);
+  free(info->smallest_range_del_key);
+  free(info->largest_range_del_key);
+}
+
+/* Table Properties */
+
+static void gorocksdb_tableproperties_fill(const TableProperties& rep, gorocksdb_tableproperties_t* props) {
+  props->data_size = rep.data_size;
+  props->index_size = rep.index_size;
+  props->filter_size = rep.filter_size;
+  props->raw_key_size = rep.raw_key_size;
+  props->raw_value_size = rep.raw_value_size;
+  props->num_data_blocks = rep.num_data_blocks;
+  props->num_entries = rep.num_entries;
+  props->num_deletions = rep.num_deletions;
+  props->num_merge_operands = rep.num_merge_operands;
+  props->num_range_deletions = rep.num_range_deletions;
+  props->format_version = rep.format_version;
+  props->creation_time = rep.creation_time;
+  props->oldest_key_time = rep.oldest_key_time;
+  props->file_creation_time = rep.file_creation_time;
+  props->column_family_name = strdup(rep.column_family_name.c_str());
+  props->comparator_name = strdup(rep.comparator_name.c_str());
+  props->merge_operator_name = strdup(rep.merge_operator_name.c_str());
+  props->compression_name = strdup(rep.compression_name.c_str());
+
+  size_t n = rep.user_collected_properties.size();
+  props->num_user_collected_properties = n;
+  props->user_collected_property_keys = static_cast<char**>(malloc(n * sizeof(char*)));
+  props->user_collected_property_key_lens = static_cast<size_t*>(malloc(n * sizeof(size_t)));
+  props->user_collected_property_values = static_cast<char**>(malloc(n * sizeof(char*)));
+  props->user_collected_property_value_lens = static_cast<size_t*>(malloc(n * sizeof(size_t)));
+  size_t i = 0;
+  for (const auto& prop : rep.user_collected_properties) {
+    props->user_collected_property_keys[i] =
+        gorocksdb_copy_string(prop.first, &props->user_collected_property_key_lens[i]);
+    props->user_collected_property_values[i] =
+        gorocksdb_copy_string(prop.second, &props->user_collected_property_value_lens[i]);
+    i++;
+  }
+}
+
+void gorocksdb_tableproperties_destroy(gorocksdb_tableproperties_t* props) {
+  free(props->column_family_name);
+  free(props->comparator_name);
+  free(props->merge_operator_name);
+  free(props->compression_name);
+  for (size_t i = 0; i < props->num_user_collected_properties; i++) {
+    free(props->user_collected_property_keys[i]);
+    free(props->user_collected_property_values[i]);
+  }
+  free(props->user_collected_property_keys);
+  free(props->user_collected_property_key_lens);
+  free(props->user_collected_property_values);
+  free(props->user_collected_property_value_lens);
+}
+
+struct gorocksdb_tablepropertiescollection_t {
+  std::vector<std::pair<std::string, std::shared_ptr<const TableProperties>>> rep;
+};
+
+static gorocksdb_tablepropertiescollection_t* gorocksdb_tablepropertiescollection_create(
+    const Status& s, const TablePropertiesCollection& props, char** errptr) {
+  if (!s.ok()) {
+    gorocksdb_save_error(errptr, s);
+    return nullptr;
+  }
+  return new gorocksdb_tablepropertiescollection_t{{props.begin(), props.end()}};
+}
+
+gorocksdb_tablepropertiescollection_t* gorocksdb_get_properties_of_all_tables(
+    rocksdb_t* db, rocksdb_column_family_handle_t* column_family, char** errptr) {
+  ColumnFamilyHandle* cf = gorocksdb_column_family(db, column_family);
+  TablePropertiesCollection props;
+  Status s = gorocksdb_rep<DB*>(db)->GetPropertiesOfAllTables(cf, &props);
+  return gorocksdb_tablepropertiescollection_create(s, props, errptr);
+}
+
+gorocksdb_tablepropertiescollection_t* gorocksdb_get_properties_of_tables_in_range(
+    rocksdb_t* db, rocksdb_column_family_handle_t* column_family, size_t num_ranges,
+    const char* const* start_keys, const size_t* start_key_lens,
+    const char* const* limit_keys, const size_t* limit_key_lens, char** errptr) {
+  ColumnFamilyHandle* cf = gorocksdb_column_family(db, column_family);
+  std::vector<Range> ranges(num_ranges);
+  for (size_t i = 0; i < num_ranges; i++) {
+    ranges[i] = Range(Slice(start_keys[i], start_key_lens[i]), Slice(limit_keys[i], limit_key_lens[i]));
+  }
+  TablePropertiesCollection props;
+  Status s = gorocksdb_rep<DB*>(db)->GetPropertiesOfTablesInRange(cf, ranges.data(), ranges.size(), &props);
+  return gorocksdb_tablepropertiescollection_create(s, props, errptr);
+}
+
+size_t gorocksdb_tablepropertiescollection_count(const gorocksdb_tablepropertiescollection_t* coll) {
+  return coll->rep.size();
+}
+
+const char* gorocksdb_tablepropertiescollection_name(const gorocksdb_tablepropertiescollection_t* coll, size_t index) {
+  return coll->rep[index].first.c_str();
+}
+
+void gorocksdb_tablepropertiescollection_get(const gorocksdb_tablepropertiescollection_t* coll, size_t index,
+                                             gorocksdb_tableproperties_t* props) {
+  gorocksdb_tableproperties_fill(*coll->rep[index].second, props);
+}
+
+void gorocksdb_tablepropertiescollection_destroy(gorocksdb_tablepropertiescollection_t* coll) {
+  delete coll;
+}
+
+/* SST File Reader */
+
+struct gorocksdb_sstfilereader_t {
+  SstFileReader* rep;
+};
+
+gorocksdb_sstfilereader_t* gorocksdb_sstfilereader_create(const rocksdb_options_t* opts) {
+  return new gorocksdb_sstfilereader_t{new SstFileReader(gorocksdb_rep<Options>(opts))};
+}
+
+void gorocksdb_sstfilereader_open(gorocksdb_sstfilereader_t* reader, const char* path, char** errptr) {
+  gorocksdb_save_error(errptr, reader->rep->Open(std::string(path)));
+}
+
+// Returns a rocksdb_iterator_t handle, so the iterator is used through the
+// rocksdb_iter_* functions. The handle only holds the pointer, so
+// rocksdb_iter_destroy releases it like one created by rocksdb.
+rocksdb_iterator_t* gorocksdb_sstfilereader_new_iterator(gorocksdb_sstfilereader_t* reader, const rocksdb_readoptions_t* opts) {
+  return reinterpret_cast<rocksdb_iterator_t*>(
+      new gorocksdb_handle<Iterator*>{reader->rep->NewIterator(gorocksdb_rep<ReadOptions>(opts))});
+}
+
+void gorocksdb_sstfilereader_verify_checksum(gorocksdb_sstfilereader_t* reader, char** errptr) {
+  gorocksdb_save_error(errptr, reader->rep->VerifyChecksum());
+}
+
+void gorocksdb_sstfilereader_get_table_properties(gorocksdb_sstfilereader_t* reader, gorocksdb_tableproperties_t* props) {
+  gorocksdb_tableproperties_fill(*reader->rep->GetTableProperties(), props);
+}
+
+void gorocksdb_sstfilereader_destroy(gorocksdb_sstfilereader_t* reader) {
+  delete reader->rep;
+  delete reader;
+}
+
+/* Read Options */
+
+rocksdb_readoptions_t* gorocksdb_readoptions_copy(const rocksdb_readoptions_t* opts) {
+  rocksdb_readoptions_t* copy = rocksdb_readoptions_create();
+  gorocksdb_rep<ReadOptions>(copy) = gorocksdb_rep<ReadOptions>(opts);
+  return copy;
+}
+
+void gorocksdb_readoptions_set_auto_prefix_mode(rocksdb_readoptions_t* opts, unsigned char v) {
+  gorocksdb_rep<ReadOptions>(opts).auto_prefix_mode = v;
+}
+
+void gorocksdb_readoptions_set_table_filter(rocksdb_readoptions_t* opts, uintptr_t idx, unsigned char enabled) {
+  if (!enabled) {
+    gorocksdb_rep<ReadOptions>(opts).table_filter = nullptr;
+    return;
+  }
+  gorocksdb_rep<ReadOptions>(opts).table_filter = [idx](const TableProperties& rep) {
+    // the Go side releases the strings of props
+    gorocksdb_tableproperties_t props;
+    gorocksdb_tableproperties_fill(rep, &props);
+    return gorocksdb_readoptions_table_filter(idx, &props) != 0;
+  };
+}
+
+/* Transaction */
+
+void gorocksdb_transaction_singledelete(rocksdb_transaction_t* txn, const char* key, size_t klen, char** errptr) {
+  gorocksdb_save_error(errptr, gorocksdb_rep<Transaction*>(txn)->SingleDelete(Slice(key, klen)));
+}
+
+void gorocksdb_transaction_singledelete_cf(rocksdb_transaction_t* txn, rocksdb_column_family_handle_t* column_family,
+                                           const char* key, size_t klen, char** errptr) {
+  gorocksdb_save_error(errptr, gorocksdb_rep<Transaction*>(txn)->SingleDelete(gorocksdb_rep<ColumnFamilyHandle*>(column_family), Slice(key, klen)));
+}
+
+// Frees a handle returned by rocksdb_transactiondb_get_prepared_transactions
+// without deleting the transaction, which may still be owned by another
+// handle. The handle only holds the pointer, so freeing it as
+// gorocksdb_handle releases the same allocation.
+void gorocksdb_transaction_free_handle(rocksdb_transaction_t* txn) {
+  delete reinterpret_cast<gorocksdb_handle<Transaction*>*>(txn);
+}
+
+/* Compaction */
+
+void gorocksdb_compactoptions_set_allow_write_stall(rocksdb_compactoptions_t* opts, unsigned char v) {
+  gorocksdb_rep<CompactRangeOptions>(opts).allow_write_stall = v;
+}
+
+void gorocksdb_compactoptions_set_max_subcompactions(rocksdb_compactoptions_t* opts, uint32_t v) {
+  gorocksdb_rep<CompactRangeOptions>(opts).max_subcompactions = v;
+}
+
+struct gorocksdb_cancel_flag_t {
+  std::atomic<bool> rep{false};
+};
+
+gorocksdb_cancel_flag_t* gorocksdb_cancel_flag_create() {
+  return new gorocksdb_cancel_flag_t;
+}
+
+void gorocksdb_cancel_flag_set(gorocksdb_cancel_flag_t* flag) {
+  flag->rep.store(true, std::memory_order_release);
+}
+
+void gorocksdb_cancel_flag_destroy(gorocksdb_cancel_flag_t* flag) {
+  delete flag;
+}
+
+// Runs a manual compaction with a copy of opts, the default options if opts
+// is null. Setting canceled cancels only this compaction.
+void gorocksdb_compact_range(rocksdb_t* db, rocksdb_column_family_handle_t* column_family,
+                             const rocksdb_compactoptions_t* opts, const char* start_key, size_t start_key_len,
+                             const char* limit_key, size_t limit_key_len, gorocksdb_cancel_flag_t* canceled,
+                             char** errptr) {
+  CompactRangeOptions rep;
+  if (opts != nullptr) {
+    rep = gorocksdb_rep<CompactRangeOptions>(opts);
+  }
+  if (canceled != nullptr) {
+    rep.canceled = &canceled->rep;
+  }
+  Slice start(start_key, start_key_len);
+  Slice limit(limit_key, limit_key_len);
+  gorocksdb_save_error(errptr, gorocksdb_rep<DB*>(db)->CompactRange(
+      rep, gorocksdb_column_family(db, column_family),
+      start_key != nullptr ? &start : nullptr, limit_key != nullptr ? &limit : nullptr));
+}
+
+void gorocksdb_compact_files(rocksdb_t* db, rocksdb_column_family_handle_t* column_family, const char* const* names,
+                             size_t num_names, int output_level, char** errptr) {
+  std::vector<std::string> input_file_names(names, names + num_names);
+  ColumnFamilyHandle* cf = gorocksdb_column_family(db, column_family);
+  gorocksdb_save_error(errptr, gorocksdb_rep<DB*>(db)->CompactFiles(CompactionOptions(), cf, input_file_names, output_level));
+}
+
+/* Metadata */
+
+static void gorocksdb_sstfilemetadata_fill(const SstFileMetaData& rep, gorocksdb_sstfilemetadata_t* meta) {
+  meta->name = strdup(rep.name.c_str());
+  meta->size = rep.size;
+  meta->smallest_key = gorocksdb_copy_string(rep.smallestkey, &meta->smallest_key_len);
+  meta->largest_key = gorocksdb_copy_string(rep.largestkey, &meta->largest_key_len);
+  meta->smallest_seqno = rep.smallest_seqno;
+  meta->largest_seqno = rep.largest_seqno;
+  meta->num_entries = rep.num_entries;
+  meta->num_deletions = rep.num_deletions;
+  meta->being_compacted = rep.being_compacted;
+  meta->file_creation_time = rep.file_creation_time;
+}
+
+void gorocksdb_sstfilemetadata_destroy(gorocksdb_sstfilemetadata_t* meta) {
+  free(meta->name);
+  free(meta->smallest_key);
+  free(meta->largest_key);
+}
+
+struct gorocksdb_livefiles_t {
+  std::vector<LiveFileMetaData> rep;
+};
+
+gorocksdb_livefiles_t* gorocksdb_livefiles(rocksdb_t* db) {
+  gorocksdb_livefiles_t* lf = new gorocksdb_livefiles_t;
+  gorocksdb_rep<DB*>(db)->GetLiveFilesMetaData(&lf->rep);
+  return lf;
+}
+
+size_t gorocksdb_livefiles_count(const gorocksdb_livefiles_t* lf) {
+  return lf->rep.size();
+}
+
+void gorocksdb_livefiles_metadata(const gorocksdb_livefiles_t* lf, size_t index, const char** column_family_name,
+                                  int* level, gorocksdb_sstfilemetadata_t* meta) {
+  const LiveFileMetaData& rep = lf->rep[index];
+  *column_family_name = rep.column_family_name.c_str();
+  *level = rep.level;
+  gorocksdb_sstfilemetadata_fill(rep, meta);
+}
+
+void gorocksdb_livefiles_destroy(gorocksdb_livefiles_t* lf) {
+  delete lf;
+}
+
+struct gorocksdb_column_family_metadata_t {
+  ColumnFamilyMetaData rep;
+};
+
+gorocksdb_column_family_metadata_t* gorocksdb_get_column_family_metadata(rocksdb_t* db,
+                                                                         rocksdb_column_family_handle_t* column_family) {
+  gorocksdb_column_family_metadata_t* cf_meta = new gorocksdb_column_family_metadata_t;
+  gorocksdb_rep<DB*>(db)->GetColumnFamilyMetaData(gorocksdb_column_family(db, column_family), &cf_meta->rep);
+  return cf_meta;
+}
+
+const char* gorocksdb_column_family_metadata_name(const gorocksdb_column_family_metadata_t* cf_meta) {
+  return cf_meta->rep.name.c_str();
+}
+
+uint64_t gorocksdb_column_family_metadata_size(const gorocksdb_column_family_metadata_t* cf_meta) {
+  return cf_meta->rep.size;
+}
+
+size_t gorocksdb_column_family_metadata_file_count(const gorocksdb_column_family_metadata_t* cf_meta) {
+  return cf_meta->rep.file_count;
+}
+
+size_t gorocksdb_column_family_metadata_level_count(const gorocksdb_column_family_metadata_t* cf_meta) {
+  return cf_meta->rep.levels.size();
+}
+
+void gorocksdb_column_family_metadata_level(const gorocksdb_column_family_metadata_t* cf_meta, size_t index,
+                                            int* level, uint64_t* size, size_t* file_count) {
+  const LevelMetaData& rep = cf_meta->rep.levels[index];
+  *level = rep.level;
+  *size = rep.size;
+  *file_count = rep.files.size();
+}
+
+void gorocksdb_column_family_metadata_file(const gorocksdb_column_family_metadata_t* cf_meta, size_t level_index,
+                                           size_t file_index, gorocksdb_sstfilemetadata_t* meta) {
+  gorocksdb_sstfilemetadata_fill(cf_meta->rep.levels[level_index].files[file_index], meta);
+}
+
+void gorocksdb_column_family_metadata_destroy(gorocksdb_column_family_metadata_t* cf_meta) {
+  delete cf_meta;
+}
+
+/* Table Properties Collector */
+
+static int gorocksdb_table_entry_type(EntryType type) {
+  switch (type) {
+    case kEntryPut:
+      return 0;
+    case kEntryDelete:
+      return 1;
+    case kEntrySingleDelete:
+      return 2;
+    case kEntryMerge:
+      return 3;
+    case kEntryRangeDeletion:
+      return 4;
+    case kEntryBlobIndex:
+      return 5;
+    default:
+      return 6;
+  }
+}
+
+// A table properties collector which calls back into go. The go side
+// releases the collector once this object is destroyed.
+class GoTablePropertiesCollector : public TablePropertiesCollector {
+ public:
+  explicit GoTablePropertiesCollector(uintptr_t idx) : idx_(idx) {}
+
+  ~GoTablePropertiesCollector() override {
+    gorocksdb_tablepropertiescollector_destroy(idx_);
+  }
+
+  Status AddUserKey(const Slice& key, const Slice& value, EntryType type,
+                    SequenceNumber seq, uint64_t file_size) override {
+    gorocksdb_tablepropertiescollector_add_user_key(
+        idx_,
+        const_cast<char*>(key.data()), key.size(),
+        const_cast<char*>(value.data()), value.size(),
+        gorocksdb_table_entry_type(type), seq, file_size);
+    return Status::OK();
+  }
+
+  Status Finish(UserCollectedProperties* properties) override {
+    gorocksdb_tablepropertiescollector_finish(idx_, properties);
+    return Status::OK();
+  }
+
+  UserCollectedProperties GetReadableProperties() const override {
+    return UserCollectedProperties();
+  }
+
+  const char* Name() const override {
+    return gorocksdb_tablepropertiescollector_name(idx_);
+  }
+
+ private:
+  uintptr_t idx_;
+};
+
+class GoTablePropertiesCollectorFactory : public TablePropertiesCollectorFactory {
+ public:
+  explicit GoTablePropertiesCollectorFactory(uintptr_t idx) : idx_(idx) {}
+
+  TablePropertiesCollector* CreateTablePropertiesCollector(
+      TablePropertiesCollectorFactory::Context context) override {
+    uintptr_t collector_idx = gorocksdb_tablepropertiescollectorfactory_create_collector(
+        idx_, context.column_family_id, context.level_at_creation);
+    // 0 means the go factory returned no collector for this table
+    if (collector_idx == 0) {
+      return nullptr;
+    }
+    return new GoTablePropertiesCollector(collector_idx);
+  }
+
+  const char* Name() const override {
+    return gorocksdb_tablepropertiescollectorfactory_name(idx_);
+  }
+
+ private:
+  uintptr_t idx_;
+};
+
+void gorocksdb_options_add_tablepropertiescollectorfactory(rocksdb_options_t* opts, uintptr_t idx) {
+  gorocksdb_rep<Options>(opts).table_properties_collector_factories.push_back(
+      std::make_shared<GoTablePropertiesCollectorFactory>(idx));
+}
+
+void gorocksdb_usercollectedproperties_add(void* properties, const char* key, size_t klen, const char* val, size_t vlen) {
+  (*static_cast<UserCollectedProperties*>(properties))[std::string(key, klen)] = std::string(val, vlen);
+}
+
+/* Env */
+
+// The hooks of a GoFileSystem, mask tells which go callbacks are set.
+struct gorocksdb_env_hooks_t {
+  uintptr_t idx;
+  unsigned char mask;
+
+  bool has(unsigned char hook) const { return (mask & hook) != 0; }
+};
+
+// Converts an error message returned by a go callback, which is allocated
+// with malloc, to a status.
+static IOStatus gorocksdb_env_status(char* err) {
+  if (err == nullptr) {
+    return IOStatus::OK();
+  }
+  IOStatus s = IOStatus::IOError(err);
+  free(err);
+  return s;
+}
+
+class GoSequentialFile : public FSSequentialFileOwnerWrapper {
+ public:
+  GoSequentialFile(std::unique_ptr<FSSequentialFile>&& file, const std::string& fname, gorocksdb_env_hooks_t hooks)
+      : FSSequentialFileOwnerWrapper(std::move(file)), fname_(fname), hooks_(hooks) {}
+
+  IOStatus Read(size_t n, const IOOptions& options, Slice* result, char* scratch, IODebugContext* dbg) override {
+    IOStatus s = target()->Read(n, options, result, scratch, dbg);
+    OnRead(s, result->size());
+    return s;
+  }
+
+  IOStatus PositionedRead(uint64_t offset, size_t n, const IOOptions& options, Slice* result, char* scratch,
+                          IODebugContext* dbg) override {
+    IOStatus s = target()->PositionedRead(offset, n, options, result, scratch, dbg);
+    OnRead(s, result->size());
+    return s;
+  }
+
+ private:
+  void OnRead(const IOStatus& s, size_t n) {
+    if (s.ok() && hooks_.has(GOROCKSDB_ENV_HOOK_READ)) {
+      gorocksdb_env_read(hooks_.idx, const_cast<char*>(fname_.c_str()), n);
+    }
+  }
+
+  std::string fname_;
+  gorocksdb_env_hooks_t hooks_;
+};
+
+class GoRandomAccessFile : public FSRandomAccessFileOwnerWrapper {
+ public:
+  GoRandomAccessFile(std::unique_ptr<FSRandomAccessFile>&& file, const std::string& fname, gorocksdb_env_hooks_t hooks)
+      : FSRandomAccessFileOwnerWrapper(std::move(file)), fname_(fname), hooks_(hooks) {}
+
+  IOStatus Read(uint64_t offset, size_t n, const IOOptions& options, Slice* result, char* scratch,
+                IODebugContext* dbg) const override {
+    IOStatus s = target()->Read(offset, n, options, result, scratch, dbg);
+    OnRead(s, result->size());
+    return s;
+  }
+
+  IOStatus MultiRead(FSReadRequest* reqs, size_t num_reqs, const IOOptions& options, IODebugContext* dbg) override {
+    IOStatus s = target()->MultiRead(reqs, num_reqs, options, dbg);
+    for (size_t i = 0; s.ok() && i < num_reqs; i++) {
+      OnRead(reqs[i].status, reqs[i].result.size());
+    }
+    return s;
+  }
+
+ private:
+  void OnRead(const IOStatus& s, size_t n) const {
+    if (s.ok() && hooks_.has(GOROCKSDB_ENV_HOOK_READ)) {
+      gorocksdb_env_read(hooks_.idx, const_cast<char*>(fname_.c_str()), n);
+    }
+  }
+
+  std::string fname_;
+  gorocksdb_env_hooks_t hooks_;
+};
+
+class GoWritableFile : public FSWritableFileOwnerWrapper {
+ public:
+  GoWritableFile(std::unique_ptr<FSWritableFile>&& file, const std::string& fname, gorocksdb_env_hooks_t hooks)
+      : FSWritableFileOwnerWrapper(std::move(file)), fname_(fname), hooks_(hooks) {}
+
+  IOStatus Append(const Slice& data, const IOOptions& options, IODebugContext* dbg) override {
+    IOStatus s = BeforeWrite(data.size());
+    return s.ok() ? target()->Append(data, options, dbg) : s;
+  }
+
+  IOStatus Append(const Slice& data, const IOOptions& options, const DataVerificationInfo& verification_info,
+                  IODebugContext* dbg) override {
+    IOStatus s = BeforeWrite(data.size());
+    return s.ok() ? target()->Append(data, options, verification_info, dbg) : s;
+  }
+
+  IOStatus PositionedAppend(const Slice& data, uint64_t offset, const IOOptions& options,
+                            IODebugContext* dbg) override {
+    IOStatus s = BeforeWrite(data.size());
+    return s.ok() ? target()->PositionedAppend(data, offset, options, dbg) : s;
+  }
+
+  IOStatus PositionedAppend(const Slice& data, uint64_t offset, const IOOptions& options,
+                            const DataVerificationInfo& verification_info, IODebugContext* dbg) override {
+    IOStatus s = BeforeWrite(data.size());
+    return s.ok() ? target()->PositionedAppend(data, offset, options, verification_info, dbg) : s;
+  }
+
+  IOStatus Sync(const IOOptions& options, IODebugContext* dbg) override {
+    IOStatus s = target()->Sync(options, dbg);
+    OnSync(s);
+    return s;
+  }
+
+  IOStatus Fsync(const IOOptions& options, IODebugContext* dbg) override {
+    IOStatus s = target()->Fsync(options, dbg);
+    OnSync(s);
+    return s;
+  }
+
+ private:
+  IOStatus BeforeWrite(size_t n) {
+    if (!hooks_.has(GOROCKSDB_ENV_HOOK_WRITE)) {
+      return IOStatus::OK();
+    }
+    return gorocksdb_env_status(gorocksdb_env_write(hooks_.idx, const_cast<char*>(fname_.c_str()), n));
+  }
+
+  void OnSync(const IOStatus& s) {
+    if (s.ok() && hooks_.has(GOROCKSDB_ENV_HOOK_SYNC)) {
+      gorocksdb_env_sync(hooks_.idx, const_cast<char*>(fname_.c_str()));
+    }
+  }
+
+  std::string fname_;
+  gorocksdb_env_hooks_t hooks_;
+};
+
+class GoFileSystem : public FileSystemWrapper {
+ public:
+  GoFileSystem(const std::shared_ptr<FileSystem>& target, gorocksdb_env_hooks_t hooks)
+      : FileSystemWrapper(target), hooks_(hooks) {}
+
+  const char* Name() const override { return "GoFileSystem"; }
+
+  IOStatus NewSequentialFile(const std::string& fname, const FileOptions& file_opts,
+                             std::unique_ptr<FSSequentialFile>* result, IODebugContext* dbg) override {
+    IOStatus s = BeforeOpen(fname, GOROCKSDB_FILE_OPEN_SEQUENTIAL);
+    std::unique_ptr<FSSequentialFile> file;
+    if (s.ok()) {
+      s = target()->NewSequentialFile(fname, file_opts, &file, dbg);
+    }
+    if (s.ok()) {
+      result->reset(new GoSequentialFile(std::move(file), fname, hooks_));
+    }
+    return s;
+  }
+
+  IOStatus NewRandomAccessFile(const std::string& fname, const FileOptions& file_opts,
+                               std::unique_ptr<FSRandomAccessFile>* result, IODebugContext* dbg) override {
+    IOStatus s = BeforeOpen(fname, GOROCKSDB_FILE_OPEN_RANDOM_ACCESS);
+    std::unique_ptr<FSRandomAccessFile> file;
+    if (s.ok()) {
+      s = target()->NewRandomAccessFile(fname, file_opts, &file, dbg);
+    }
+    if (s.ok()) {
+      result->reset(new GoRandomAccessFile(std::move(file), fname, hooks_));
+    }
+    return s;
+  }
+
+  IOStatus NewWritableFile(const std::string& fname, const FileOptions& file_opts,
+                           std::unique_ptr<FSWritableFile>* result, IODebugContext* dbg) override {
+    IOStatus s = BeforeOpen(fname, GOROCKSDB_FILE_OPEN_WRITABLE);
+    std::unique_ptr<FSWritableFile> file;
+    if (s.ok()) {
+      s = target()->NewWritableFile(fname, file_opts, &file, dbg);
+    }
+    if (s.ok()) {
+      result->reset(new GoWritableFile(std::move(file), fname, hooks_));
+    }
+    return s;
+  }
+
+  IOStatus ReopenWritableFile(const std::string& fname, const FileOptions& file_opts,
+                              std::unique_ptr<FSWritableFile>* result, IODebugContext* dbg) override {
+    IOStatus s = BeforeOpen(fname, GOROCKSDB_FILE_OPEN_WRITABLE);
+    std::unique_ptr<FSWritableFile> file;
+    if (s.ok()) {
+      s = target()->ReopenWritableFile(fname, file_opts, &file, dbg);
+    }
+    if (s.ok()) {
+      result->reset(new GoWritableFile(std::move(file), fname, hooks_));
+    }
+    return s;
+  }
+
+  IOStatus ReuseWritableFile(const std::string& fname, const std::string& old_fname, const FileOptions& file_opts,
+                             std::unique_ptr<FSWritableFile>* result, IODebugContext* dbg) override {
+    IOStatus s = BeforeOpen(fname, GOROCKSDB_FILE_OPEN_WRITABLE);
+    std::unique_ptr<FSWritableFile> file;
+    if (s.ok()) {
+      s = target()->ReuseWritableFile(fname, old_fname, file_opts, &file, dbg);
+    }
+    if (s.ok()) {
+      result->reset(new GoWritableFile(std::move(file), fname, hooks_));
+    }
+    return s;
+  }
+
+  IOStatus RenameFile(const std::string& src, const std::string& dest, const IOOptions& options,
+                      IODebugContext* dbg) override {
+    if (hooks_.has(GOROCKSDB_ENV_HOOK_RENAME)) {
+      IOStatus s = gorocksdb_env_status(gorocksdb_env_rename(
+          hooks_.idx, const_cast<char*>(src.c_str()), const_cast<char*>(dest.c_str())));
+      if (!s.ok()) {
+        return s;
+      }
+    }
+    return target()->RenameFile(src, dest, options, dbg);
+  }
+
+  IOStatus DeleteFile(const std::string& fname, const IOOptions& options, IODebugContext* dbg) override {
+    if (hooks_.has(GOROCKSDB_ENV_HOOK_DELETE)) {
+      IOStatus s = gorocksdb_env_status(gorocksdb_env_delete(hooks_.idx, const_cast<char*>(fname.c_str())));
+      if (!s.ok()) {
+        return s;
+      }
+    }
+    return target()->DeleteFile(fname, options, dbg);
+  }
+
+ private:
+  IOStatus BeforeOpen(const std::string& fname, int mode) {
+    if (!hooks_.has(GOROCKSDB_ENV_HOOK_OPEN)) {
+      return IOStatus::OK();
+    }
+    return gorocksdb_env_status(gorocksdb_env_open(hooks_.idx, const_cast<char*>(fname.c_str()), mode));
+  }
+
+  gorocksdb_env_hooks_t hooks_;
+};
+
+struct gorocksdb_env_t {
+  std::unique_ptr<Env> rep;
+};
+
+gorocksdb_env_t* gorocksdb_create_hooked_env(uintptr_t idx, unsigned char mask) {
+  std::shared_ptr<FileSystem> fs = std::make_shared<GoFileSystem>(FileSystem::Default(), gorocksdb_env_hooks_t{idx, mask});
+  return new gorocksdb_env_t{NewCompositeEnv(fs)};
+}
+
+void gorocksdb_env_set_background_threads(gorocksdb_env_t* env, int n) {
+  env->rep->SetBackgroundThreads(n, Env::LOW);
+}
+
+void gorocksdb_env_set_high_priority_background_threads(gorocksdb_env_t* env, int n) {
+  env->rep->SetBackgroundThreads(n, Env::HIGH);
+}
+
+void gorocksdb_env_destroy(gorocksdb_env_t* env) {
+  delete env;
+}
+
+void gorocksdb_options_set_env(rocksdb_options_t* opts, gorocksdb_env_t* env) {
+  gorocksdb_rep<Options>(opts).env = env->rep.get();
+}
+
+/* Statistics */
+
+// Indexed by the Ticker constants in statistics.go.
+static const Tickers gorocksdb_tickers[] = {
+    BLOCK_CACHE_MISS,
+    BLOCK_CACHE_HIT,
+    BLOCK_CACHE_ADD,
+    BLOCK_CACHE_ADD_FAILURES,
+    BLOCK_CACHE_INDEX_MISS,
+    BLOCK_CACHE_INDEX_HIT,
+    BLOCK_CACHE_FILTER_MISS,
+    BLOCK_CACHE_FILTER_HIT,
+    BLOCK_CACHE_DATA_MISS,
+    BLOCK_CACHE_DATA_HIT,
+    BLOCK_CACHE_BYTES_READ,
+    BLOCK_CACHE_BYTES_WRITE,
+    BLOOM_FILTER_USEFUL,
+    MEMTABLE_HIT,
+    MEMTABLE_MISS,
+    GET_HIT_L0,
+    GET_HIT_L1,
+    GET_HIT_L2_AND_UP,
+    COMPACTION_KEY_DROP_NEWER_ENTRY,
+    COMPACTION_KEY_DROP_OBSOLETE,
+    COMPACTION_KEY_DROP_RANGE_DEL,
+    COMPACTION_KEY_DROP_USER,
+    NUMBER_KEYS_WRITTEN,
+    NUMBER_KEYS_READ,
+    NUMBER_KEYS_UPDATED,
+    BYTES_WRITTEN,
+    BYTES_READ,
+    NUMBER_DB_SEEK,
+    NUMBER_DB_NEXT,
+    NUMBER_DB_PREV,
+    STALL_MICROS,
+    WAL_FILE_SYNCED,
+    WAL_FILE_BYTES,
+    COMPACT_READ_BYTES,
+    COMPACT_WRITE_BYTES,
+    FLUSH_WRITE_BYTES,
+    NUMBER_MULTIGET_CALLS,
+    NUMBER_MULTIGET_KEYS_READ,
+    NUMBER_MULTIGET_BYTES_READ,
+    NUMBER_MERGE_FAILURES,
+};
+
+// Indexed by the Histogram constants in statistics.go.
+static const Histograms gorocksdb_histograms[] = {
+    DB_GET,
+    DB_WRITE,
+    DB_MULTIGET,
+    DB_SEEK,
+    COMPACTION_TIME,
+    FLUSH_TIME,
+    WRITE_STALL,
+    WAL_FILE_SYNC_MICROS,
+    TABLE_SYNC_MICROS,
+    SST_READ_MICROS,
+    BYTES_PER_READ,
+    BYTES_PER_WRITE,
+    BYTES_PER_MULTIGET,
+};
+
+uint32_t gorocksdb_ticker_type(int ticker) {
+  return gorocksdb_tickers[ticker];
+}
+
+uint32_t gorocksdb_histogram_type(int histogram) {
+  return gorocksdb_histograms[histogram];
+}
+
+const char* gorocksdb_ticker_name(int ticker) {
+  for (const auto& entry : TickersNameMap) {
+    if (entry.first == gorocksdb_tickers[ticker]) {
+      return entry.second.c_str();
+    }
+  }
+  return "";
+}
+
+const char* gorocksdb_histogram_name(int histogram) {
+  for (const auto& entry : HistogramsNameMap) {
+    if (entry.first == gorocksdb_histograms[histogram]) {
+      return entry.second.c_str();
+    }
+  }
+  return "";
+}
+
+void gorocksdb_options_statistics_reset(rocksdb_options_t* opts, char** errptr) {
+  const std::shared_ptr<Statistics>& statistics = gorocksdb_rep<Options>(opts).statistics;
+  if (statistics != nullptr) {
+    gorocksdb_save_error(errptr, statistics->Reset());
+  }
+}
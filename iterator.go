@@ -5,7 +5,7 @@ package gorocksdb
 import "C"
 import (
 	"bytes"
-	"errors"
+	"context"
 	"unsafe"
 )
 
@@ -28,23 +28,37 @@ import (
 //
 type Iterator struct {
 	c *C.rocksdb_iterator_t
+
+	ctx    context.Context
+	ctxErr error
 }
 
 // NewNativeIterator creates a Iterator object.
 func NewNativeIterator(c unsafe.Pointer) *Iterator {
-	return &Iterator{(*C.rocksdb_iterator_t)(c)}
+	return &Iterator{c: (*C.rocksdb_iterator_t)(c)}
+}
+
+// SetContext bounds the iteration by ctx: once ctx is done, Valid and
+// ValidForPrefix return false and Err returns ctx.Err(), which stops a scan
+// between two steps.
+func (iter *Iterator) SetContext(ctx context.Context) {
+	iter.ctx = ctx
+	iter.ctxErr = nil
 }
 
 // Valid returns false only when an Iterator has iterated past either the
 // first or the last key in the database.
 func (iter *Iterator) Valid() bool {
+	if iter.done() {
+		return false
+	}
 	return C.rocksdb_iter_valid(iter.c) != 0
 }
 
 // ValidForPrefix returns false only when an Iterator has iterated past the
 // first or the last key in the database or the specified prefix.
 func (iter *Iterator) ValidForPrefix(prefix []byte) bool {
-	if C.rocksdb_iter_valid(iter.c) == 0 {
+	if iter.done() || C.rocksdb_iter_valid(iter.c) == 0 {
 		return false
 	}
 
@@ -54,6 +68,20 @@ func (iter *Iterator) ValidForPrefix(prefix []byte) bool {
 	return result
 }
 
+// done reports whether the context of the iterator is done.
+func (iter *Iterator) done() bool {
+	if iter.ctx == nil {
+		return false
+	}
+	select {
+	case <-iter.ctx.Done():
+		iter.ctxErr = iter.ctx.Err()
+		return true
+	default:
+		return false
+	}
+}
+
 // Key returns the key the iterator currently holds.
 func (iter *Iterator) Key() *Slice {
 	var cLen C.size_t
@@ -114,9 +142,9 @@ func (iter *Iterator) Err() error {
 	C.rocksdb_iter_get_error(iter.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
-	return nil
+	return iter.ctxErr
 }
 
 // Close closes the iterator.
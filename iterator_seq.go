@@ -0,0 +1,176 @@
+//go:build go1.23
+// +build go1.23
+
+package gorocksdb
+
+import (
+	"bytes"
+	"iter"
+)
+
+// Scan creates range-over-func sequences over the key-values of a database,
+// column family or transaction.
+//
+// Each range over a sequence creates a new Iterator which is closed when the
+// loop ends. The yielded keys and values are copies and remain valid after
+// the loop. The error function returned with a sequence reports the
+// iterator error of the last range over it.
+//
+// A sequence and its error function share state and are not safe for
+// concurrent use. Range over a sequence from one goroutine at a time, or
+// create a sequence per goroutine.
+//
+// Range and Prefix assume the default byte-wise ordering of keys. They
+// create their iterators with iterate bounds, narrowed to the iterate
+// bounds of the ReadOptions if those are tighter, so RocksDB does not read
+// past the range in either direction.
+//
+// For example:
+//
+//	items, errFn := db.Scan(ro).Prefix([]byte("user/"))
+//	for k, v := range items {
+//		fmt.Printf("Key: %s Value: %s\n", k, v)
+//	}
+//	if err := errFn(); err != nil {
+//		return err
+//	}
+type Scan struct {
+	opts        *ReadOptions
+	newIterator func(opts *ReadOptions) *Iterator
+}
+
+// Scan returns a Scan over the database.
+func (db *DB) Scan(opts *ReadOptions) Scan {
+	return Scan{opts, func(opts *ReadOptions) *Iterator { return db.NewIterator(opts) }}
+}
+
+// ScanCF returns a Scan over the column family.
+func (db *DB) ScanCF(opts *ReadOptions, cf *ColumnFamilyHandle) Scan {
+	return Scan{opts, func(opts *ReadOptions) *Iterator { return db.NewIteratorCF(opts, cf) }}
+}
+
+// All returns all key-values of the database in order.
+func (db *DB) All(opts *ReadOptions) (iter.Seq2[[]byte, []byte], func() error) {
+	return db.Scan(opts).All()
+}
+
+// Range returns the key-values of the database in [start, end) in order.
+// A nil start or end means the range is unbounded on that side.
+func (db *DB) Range(opts *ReadOptions, start, end []byte) (iter.Seq2[[]byte, []byte], func() error) {
+	return db.Scan(opts).Range(start, end)
+}
+
+// Prefix returns the key-values of the database whose keys start with
+// prefix in order.
+func (db *DB) Prefix(opts *ReadOptions, prefix []byte) (iter.Seq2[[]byte, []byte], func() error) {
+	return db.Scan(opts).Prefix(prefix)
+}
+
+// Scan returns a Scan over the transaction, including its own writes.
+func (transaction *Transaction) Scan(opts *ReadOptions) Scan {
+	return Scan{opts, func(opts *ReadOptions) *Iterator { return transaction.NewIterator(opts) }}
+}
+
+// ScanCF returns a Scan over the column family in the transaction,
+// including its own writes.
+func (transaction *Transaction) ScanCF(opts *ReadOptions, cf *ColumnFamilyHandle) Scan {
+	return Scan{opts, func(opts *ReadOptions) *Iterator { return transaction.NewIteratorCF(opts, cf) }}
+}
+
+// All returns all key-values in order.
+func (s Scan) All() (iter.Seq2[[]byte, []byte], func() error) {
+	return s.scan(nil, nil, false)
+}
+
+// AllReverse returns all key-values in reverse order.
+func (s Scan) AllReverse() (iter.Seq2[[]byte, []byte], func() error) {
+	return s.scan(nil, nil, true)
+}
+
+// Range returns the key-values in [start, end) in order.
+// A nil start or end means the range is unbounded on that side.
+func (s Scan) Range(start, end []byte) (iter.Seq2[[]byte, []byte], func() error) {
+	return s.scan(start, end, false)
+}
+
+// RangeReverse returns the key-values in [start, end) in reverse order.
+// A nil start or end means the range is unbounded on that side.
+func (s Scan) RangeReverse(start, end []byte) (iter.Seq2[[]byte, []byte], func() error) {
+	return s.scan(start, end, true)
+}
+
+// Prefix returns the key-values whose keys start with prefix in order.
+func (s Scan) Prefix(prefix []byte) (iter.Seq2[[]byte, []byte], func() error) {
+	return s.scan(prefix, prefixSuccessor(prefix), false)
+}
+
+// PrefixReverse returns the key-values whose keys start with prefix in
+// reverse order.
+func (s Scan) PrefixReverse(prefix []byte) (iter.Seq2[[]byte, []byte], func() error) {
+	return s.scan(prefix, prefixSuccessor(prefix), true)
+}
+
+func (s Scan) scan(start, end []byte, reverse bool) (iter.Seq2[[]byte, []byte], func() error) {
+	// shared by all ranges over seq, see the Scan docs
+	var err error
+	seq := func(yield func(key, value []byte) bool) {
+		opts := s.opts
+		if start != nil || end != nil {
+			opts = opts.withBounds(start, end)
+			defer opts.Destroy()
+		}
+		it := s.newIterator(opts)
+		defer it.Close()
+
+		err = nil
+		switch {
+		case reverse && end != nil:
+			it.SeekForPrev(end)
+			if it.Valid() && bytes.Equal(it.Key().Data(), end) {
+				it.Prev()
+			}
+		case reverse:
+			it.SeekToLast()
+		case start != nil:
+			it.Seek(start)
+		default:
+			it.SeekToFirst()
+		}
+
+		for ; it.Valid(); stepIterator(it, reverse) {
+			key := it.Key().Data()
+			if !reverse && end != nil && bytes.Compare(key, end) >= 0 {
+				break
+			}
+			if reverse && start != nil && bytes.Compare(key, start) < 0 {
+				break
+			}
+			if !yield(bytes.Clone(key), bytes.Clone(it.Value().Data())) {
+				return
+			}
+		}
+		err = it.Err()
+	}
+	return seq, func() error { return err }
+}
+
+func stepIterator(it *Iterator, reverse bool) {
+	if reverse {
+		it.Prev()
+	} else {
+		it.Next()
+	}
+}
+
+// prefixSuccessor returns the smallest key greater than all keys starting
+// with prefix, or nil if there is none.
+func prefixSuccessor(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			end := bytes.Clone(prefix[:i+1])
+			end[i]++
+			return end
+		}
+	}
+	return nil
+}
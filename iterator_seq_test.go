@@ -0,0 +1,69 @@
+//go:build go1.23
+// +build go1.23
+
+package gorocksdb
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestIteratorSeq(t *testing.T) {
+	db := newTestDB(t, "TestIteratorSeq", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for _, k := range []string{"a", "b1", "b2", "b3", "c", "d"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte("v"+k)))
+	}
+
+	ro := NewDefaultReadOptions()
+	collect := func(seq iter.Seq2[[]byte, []byte], errFn func() error) []string {
+		var keys []string
+		for k, v := range seq {
+			ensure.DeepEqual(t, string(v), "v"+string(k))
+			keys = append(keys, string(k))
+		}
+		ensure.Nil(t, errFn())
+		return keys
+	}
+
+	ensure.DeepEqual(t, collect(db.All(ro)), []string{"a", "b1", "b2", "b3", "c", "d"})
+	ensure.DeepEqual(t, collect(db.Range(ro, []byte("b2"), []byte("d"))), []string{"b2", "b3", "c"})
+	ensure.DeepEqual(t, collect(db.Range(ro, nil, []byte("b2"))), []string{"a", "b1"})
+	ensure.DeepEqual(t, collect(db.Prefix(ro, []byte("b"))), []string{"b1", "b2", "b3"})
+
+	s := db.Scan(ro)
+	ensure.DeepEqual(t, collect(s.AllReverse()), []string{"d", "c", "b3", "b2", "b1", "a"})
+	ensure.DeepEqual(t, collect(s.RangeReverse([]byte("b2"), []byte("d"))), []string{"c", "b3", "b2"})
+	ensure.DeepEqual(t, collect(s.RangeReverse([]byte("c"), nil)), []string{"d", "c"})
+	ensure.DeepEqual(t, collect(s.PrefixReverse([]byte("b"))), []string{"b3", "b2", "b1"})
+
+	// the bounds of the read options narrow the ranges
+	bounded := NewDefaultReadOptions()
+	defer bounded.Destroy()
+	bounded.SetIterateLowerBound([]byte("b2"))
+	bounded.SetIterateUpperBound([]byte("c"))
+	ensure.DeepEqual(t, collect(db.Range(bounded, []byte("a"), []byte("d"))), []string{"b2", "b3"})
+	ensure.DeepEqual(t, collect(db.Scan(bounded).RangeReverse([]byte("b3"), nil)), []string{"b3"})
+	ensure.DeepEqual(t, collect(db.Scan(bounded).PrefixReverse([]byte("b"))), []string{"b3", "b2"})
+
+	// breaking out of the loop closes the iterator, the keys are copies
+	var first []byte
+	seq, errFn := db.All(ro)
+	for k := range seq {
+		first = k
+		break
+	}
+	ensure.Nil(t, errFn())
+	ensure.DeepEqual(t, first, []byte("a"))
+}
+
+func TestPrefixSuccessor(t *testing.T) {
+	ensure.DeepEqual(t, prefixSuccessor([]byte("ab")), []byte("ac"))
+	ensure.DeepEqual(t, prefixSuccessor([]byte{'a', 0xff}), []byte("b"))
+	ensure.True(t, prefixSuccessor([]byte{0xff, 0xff}) == nil)
+	ensure.True(t, prefixSuccessor(nil) == nil)
+}
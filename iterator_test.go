@@ -1,6 +1,7 @@
 package gorocksdb
 
 import (
+	"context"
 	"testing"
 
 	"github.com/facebookgo/ensure"
@@ -29,3 +30,118 @@ func TestIterator(t *testing.T) {
 	ensure.Nil(t, iter.Err())
 	ensure.DeepEqual(t, actualKeys, givenKeys)
 }
+
+func TestIteratorContext(t *testing.T) {
+	db := newTestDB(t, "TestIteratorContext", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for _, k := range []string{"key1", "key2", "key3"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte("val")))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ro := NewDefaultReadOptions()
+	iter := db.NewIterator(ro)
+	defer iter.Close()
+	iter.SetContext(ctx)
+
+	var n int
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		n++
+		if n == 2 {
+			cancel()
+		}
+	}
+	ensure.DeepEqual(t, n, 2)
+	ensure.DeepEqual(t, iter.Err(), context.Canceled)
+}
+
+func TestIteratorBounds(t *testing.T) {
+	db := newTestDB(t, "TestIteratorBounds", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for _, k := range []string{"key1", "key2", "key3", "key4"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte("val")))
+	}
+
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	lower, upper := []byte("key2"), []byte("key4")
+	ro.SetIterateLowerBound(lower)
+	ro.SetIterateUpperBound(upper)
+	// the bounds are copied
+	lower[3], upper[3] = '1', '9'
+
+	iter := db.NewIterator(ro)
+	defer iter.Close()
+	var keys []string
+	for iter.SeekToLast(); iter.Valid(); iter.Prev() {
+		keys = append(keys, string(iter.Key().Data()))
+	}
+	ensure.Nil(t, iter.Err())
+	ensure.DeepEqual(t, keys, []string{"key3", "key2"})
+}
+
+func TestIteratorTableFilter(t *testing.T) {
+	db := newTestDB(t, "TestIteratorTableFilter", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("val")))
+
+	var calls int
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetTableFilter(func(props *TableProperties) bool {
+		calls++
+		ensure.DeepEqual(t, props.NumEntries, uint64(1))
+		return false
+	})
+
+	iter := db.NewIterator(ro)
+	defer iter.Close()
+	var keys []string
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		keys = append(keys, string(iter.Key().Data()))
+	}
+	ensure.Nil(t, iter.Err())
+	ensure.DeepEqual(t, keys, []string{"key2"})
+	ensure.DeepEqual(t, calls, 1)
+}
+
+func TestReadOptionsWithBounds(t *testing.T) {
+	db := newTestDB(t, "TestReadOptionsWithBounds", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for _, k := range []string{"key1", "key2", "key3", "key4"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte("val")))
+	}
+
+	ro := NewDefaultReadOptions()
+	ro.SetIterateLowerBound([]byte("key2"))
+	ro.SetIterateUpperBound([]byte("key9"))
+	ro.SetTableFilter(func(props *TableProperties) bool { return true })
+	bounded := ro.withBounds(nil, []byte("key4"))
+	defer bounded.Destroy()
+	filter := ro.tableFilter
+	// the copy owns its bounds and table filter
+	ro.Destroy()
+	_, ok := loadTableFilter(filter)
+	ensure.False(t, ok)
+	_, ok = loadTableFilter(bounded.tableFilter)
+	ensure.True(t, ok)
+
+	iter := db.NewIterator(bounded)
+	defer iter.Close()
+	var keys []string
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		keys = append(keys, string(iter.Key().Data()))
+	}
+	ensure.Nil(t, iter.Err())
+	ensure.DeepEqual(t, keys, []string{"key2", "key3"})
+}
@@ -0,0 +1,84 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"strings"
+	"sync"
+)
+
+// A Logger receives the messages RocksDB writes to its info log.
+// It is registered with Options.SetLogger.
+//
+// Log is called from the thread that logs the message, possibly
+// concurrently, and must be thread-safe.
+type Logger interface {
+	Log(level InfoLogLevel, msg string)
+}
+
+// SetLogger routes the info log of databases opened with these options to
+// the Logger instead of the LOG file. Messages below level are dropped.
+// Default: nil, RocksDB writes the LOG file in the database directory
+func (opts *Options) SetLogger(logger Logger, level InfoLogLevel) {
+	idx := registerLogger(logger)
+	C.gorocksdb_options_set_logger(opts.c, C.uintptr_t(idx), C.int(level))
+}
+
+// Hold references to loggers.
+var loggers = NewCOWList()
+
+func registerLogger(logger Logger) int {
+	return loggers.Append(logger)
+}
+
+//export gorocksdb_logger_log
+func gorocksdb_logger_log(idx int, cLevel C.int, cMsg *C.char, cMsgLen C.int) {
+	msg := strings.TrimRight(C.GoStringN(cMsg, cMsgLen), "\n")
+	loggers.Get(idx).(Logger).Log(InfoLogLevel(cLevel), msg)
+}
+
+// LogEntry is a message kept by a RingBufferLogger.
+type LogEntry struct {
+	Level   InfoLogLevel
+	Message string
+}
+
+// RingBufferLogger is a Logger which keeps the most recent messages in
+// memory, e.g. to inspect them in tests.
+type RingBufferLogger struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int
+	full    bool
+}
+
+// NewRingBufferLogger creates a RingBufferLogger keeping up to size messages.
+func NewRingBufferLogger(size int) *RingBufferLogger {
+	return &RingBufferLogger{entries: make([]LogEntry, size)}
+}
+
+// Log implements Logger.
+func (l *RingBufferLogger) Log(level InfoLogLevel, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) == 0 {
+		return
+	}
+	l.entries[l.next] = LogEntry{Level: level, Message: msg}
+	l.next++
+	if l.next == len(l.entries) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// Entries returns the kept messages, oldest first.
+func (l *RingBufferLogger) Entries() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.full {
+		return append([]LogEntry(nil), l.entries[:l.next]...)
+	}
+	return append(append([]LogEntry(nil), l.entries[l.next:]...), l.entries[:l.next]...)
+}
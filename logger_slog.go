@@ -0,0 +1,37 @@
+//go:build go1.21
+// +build go1.21
+
+package gorocksdb
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogLogger returns a Logger which writes the messages of RocksDB to l.
+// Debug, info, warn and error messages are logged with the corresponding
+// slog level, fatal messages as errors and header messages as info.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Log(level InfoLogLevel, msg string) {
+	s.l.Log(context.Background(), slogLevel(level), msg, slog.String("source", "rocksdb"))
+}
+
+func slogLevel(level InfoLogLevel) slog.Level {
+	switch level {
+	case DebugInfoLogLevel:
+		return slog.LevelDebug
+	case WarnInfoLogLevel:
+		return slog.LevelWarn
+	case ErrorInfoLogLevel, FatalInfoLogLevel:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,27 @@
+//go:build go1.21
+// +build go1.21
+
+package gorocksdb
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	logger.Log(WarnInfoLogLevel, "stalling writes")
+	logger.Log(DebugInfoLogLevel, "dropped by the handler")
+
+	out := buf.String()
+	ensure.True(t, strings.Contains(out, "level=WARN"))
+	ensure.True(t, strings.Contains(out, `msg="stalling writes"`))
+	ensure.True(t, strings.Contains(out, "source=rocksdb"))
+	ensure.False(t, strings.Contains(out, "dropped by the handler"))
+}
@@ -0,0 +1,41 @@
+package gorocksdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestLogger(t *testing.T) {
+	logger := NewRingBufferLogger(1024)
+	db := newTestDB(t, "TestLogger", func(opts *Options) {
+		opts.SetLogger(logger, InfoInfoLogLevel)
+	})
+	db.Close()
+
+	entries := logger.Entries()
+	ensure.True(t, len(entries) > 0)
+	var found bool
+	for _, e := range entries {
+		ensure.True(t, e.Level >= InfoInfoLogLevel)
+		ensure.False(t, strings.HasSuffix(e.Message, "\n"))
+		if strings.Contains(e.Message, "RocksDB version") {
+			found = true
+		}
+	}
+	ensure.True(t, found)
+}
+
+func TestRingBufferLogger(t *testing.T) {
+	logger := NewRingBufferLogger(2)
+	ensure.DeepEqual(t, len(logger.Entries()), 0)
+
+	logger.Log(InfoInfoLogLevel, "a")
+	logger.Log(WarnInfoLogLevel, "b")
+	logger.Log(ErrorInfoLogLevel, "c")
+	ensure.DeepEqual(t, logger.Entries(), []LogEntry{
+		{WarnInfoLogLevel, "b"},
+		{ErrorInfoLogLevel, "c"},
+	})
+}
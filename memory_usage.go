@@ -3,10 +3,7 @@ package gorocksdb
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
 import "C"
-import (
-	"errors"
-	"unsafe"
-)
+import "unsafe"
 
 // MemoryUsage contains memory usage statistics provided by RocksDB
 type MemoryUsage struct {
@@ -43,7 +40,7 @@ func GetApproximateMemoryUsageByType(dbs []*DB, caches []*Cache) (*MemoryUsage,
 	memoryUsage := C.rocksdb_approximate_memory_usage_create(consumers, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 
 	defer C.rocksdb_approximate_memory_usage_destroy(memoryUsage)
@@ -0,0 +1,148 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// OptimisticTransactionDB is a reusable handle to a RocksDB database on disk
+// using optimistic concurrency control, created by OpenOptimisticTransactionDb.
+//
+// Transactions do not take locks while they are running. Instead, conflicts
+// with other writers are detected at Commit, which returns an error matching
+// ErrBusy if the transaction has to be retried.
+type OptimisticTransactionDB struct {
+	c    *C.rocksdb_optimistictransactiondb_t
+	name string
+	opts *Options
+}
+
+// OpenOptimisticTransactionDb opens a database with the specified options.
+func OpenOptimisticTransactionDb(opts *Options, name string) (*OptimisticTransactionDB, error) {
+	var (
+		cErr  *C.char
+		cName = C.CString(name)
+	)
+	defer C.free(unsafe.Pointer(cName))
+	db := C.rocksdb_optimistictransactiondb_open(opts.c, cName, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return &OptimisticTransactionDB{
+		name: name,
+		c:    db,
+		opts: opts,
+	}, nil
+}
+
+// OpenOptimisticTransactionDbColumnFamilies opens a database with the specified column families.
+func OpenOptimisticTransactionDbColumnFamilies(
+	opts *Options,
+	name string,
+	cfNames []string,
+	cfOpts []*Options,
+) (*OptimisticTransactionDB, []*ColumnFamilyHandle, error) {
+	numColumnFamilies := len(cfNames)
+	if numColumnFamilies != len(cfOpts) {
+		return nil, nil, errors.New("must provide the same number of column family names and options")
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	cNames := make([]*C.char, numColumnFamilies)
+	for i, s := range cfNames {
+		cNames[i] = C.CString(s)
+	}
+	defer func() {
+		for _, s := range cNames {
+			C.free(unsafe.Pointer(s))
+		}
+	}()
+
+	cOpts := make([]*C.rocksdb_options_t, numColumnFamilies)
+	for i, o := range cfOpts {
+		cOpts[i] = o.c
+	}
+
+	cHandles := make([]*C.rocksdb_column_family_handle_t, numColumnFamilies)
+
+	var cErr *C.char
+	db := C.rocksdb_optimistictransactiondb_open_column_families(
+		opts.c,
+		cName,
+		C.int(numColumnFamilies),
+		&cNames[0],
+		&cOpts[0],
+		&cHandles[0],
+		&cErr,
+	)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, nil, newError(C.GoString(cErr))
+	}
+
+	cfHandles := make([]*ColumnFamilyHandle, numColumnFamilies)
+	for i, c := range cHandles {
+		cfHandles[i] = NewNativeColumnFamilyHandle(c)
+	}
+
+	return &OptimisticTransactionDB{
+		name: name,
+		c:    db,
+		opts: opts,
+	}, cfHandles, nil
+}
+
+// Name returns the name of the database.
+func (db *OptimisticTransactionDB) Name() string {
+	return db.name
+}
+
+// TransactionBegin begins a new transaction
+// with the WriteOptions and OptimisticTransactionOptions given.
+func (db *OptimisticTransactionDB) TransactionBegin(
+	opts *WriteOptions,
+	transactionOpts *OptimisticTransactionOptions,
+	oldTransaction *Transaction,
+) *Transaction {
+	if oldTransaction != nil {
+		return NewNativeTransaction(C.rocksdb_optimistictransaction_begin(
+			db.c,
+			opts.c,
+			transactionOpts.c,
+			oldTransaction.c,
+		))
+	}
+
+	return NewNativeTransaction(C.rocksdb_optimistictransaction_begin(
+		db.c, opts.c, transactionOpts.c, nil))
+}
+
+// GetBaseDb returns the underlying database, which can be used for
+// non-transactional reads and writes. The returned DB must be released with
+// CloseBaseDb and not with DB.Close.
+func (db *OptimisticTransactionDB) GetBaseDb() *DB {
+	return &DB{
+		name: db.name,
+		c:    C.rocksdb_optimistictransactiondb_get_base_db(db.c),
+		opts: db.opts,
+	}
+}
+
+// CloseBaseDb releases a DB returned by GetBaseDb. The database itself stays
+// open until the OptimisticTransactionDB is closed.
+func (db *OptimisticTransactionDB) CloseBaseDb(base *DB) {
+	C.rocksdb_optimistictransactiondb_close_base_db(base.c)
+	base.c = nil
+}
+
+// Close closes the database.
+func (db *OptimisticTransactionDB) Close() {
+	C.rocksdb_optimistictransactiondb_close(db.c)
+	db.c = nil
+}
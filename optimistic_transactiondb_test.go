@@ -0,0 +1,92 @@
+package gorocksdb
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestOpenOptimisticTransactionDb(t *testing.T) {
+	db := newTestOptimisticTransactionDB(t, "TestOpenOptimisticTransactionDb", nil)
+	defer db.Close()
+}
+
+func TestOptimisticTransactionDBCRUD(t *testing.T) {
+	db := newTestOptimisticTransactionDB(t, "TestOptimisticTransactionDBCRUD", nil)
+	defer db.Close()
+
+	var (
+		givenKey    = []byte("hello")
+		givenVal1   = []byte("world1")
+		givenTxnKey = []byte("hello2")
+		givenTxnVal = []byte("whatawonderful")
+		wo          = NewDefaultWriteOptions()
+		ro          = NewDefaultReadOptions()
+		to          = NewDefaultOptimisticTransactionOptions()
+	)
+
+	// non-transactional writes go through the base db
+	base := db.GetBaseDb()
+	defer db.CloseBaseDb(base)
+	ensure.Nil(t, base.Put(wo, givenKey, givenVal1))
+
+	// transaction
+	txn := db.TransactionBegin(wo, to, nil)
+	defer txn.Destroy()
+	v1, err := txn.Get(ro, givenKey)
+	defer v1.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1.Data(), givenVal1)
+
+	ensure.Nil(t, txn.Put(givenTxnKey, givenTxnVal))
+	ensure.Nil(t, txn.Commit())
+
+	v2, err := base.Get(ro, givenTxnKey)
+	defer v2.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v2.Data(), givenTxnVal)
+}
+
+func TestOptimisticTransactionDBConflict(t *testing.T) {
+	db := newTestOptimisticTransactionDB(t, "TestOptimisticTransactionDBConflict", nil)
+	defer db.Close()
+
+	var (
+		givenKey  = []byte("hello")
+		givenVal1 = []byte("world1")
+		givenVal2 = []byte("world2")
+		wo        = NewDefaultWriteOptions()
+		to        = NewDefaultOptimisticTransactionOptions()
+	)
+	to.SetSetSnapshot(true)
+
+	txn := db.TransactionBegin(wo, to, nil)
+	defer txn.Destroy()
+	ensure.Nil(t, txn.Put(givenKey, givenVal1))
+
+	// a write outside of the transaction after it started
+	base := db.GetBaseDb()
+	defer db.CloseBaseDb(base)
+	ensure.Nil(t, base.Put(wo, givenKey, givenVal2))
+
+	err := txn.Commit()
+	ensure.NotNil(t, err)
+	ensure.True(t, errors.Is(err, ErrBusy))
+}
+
+func newTestOptimisticTransactionDB(t *testing.T, name string, applyOpts func(opts *Options)) *OptimisticTransactionDB {
+	dir, err := ioutil.TempDir("", "gorocksoptimistictransactiondb-"+name)
+	ensure.Nil(t, err)
+
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissing(true)
+	if applyOpts != nil {
+		applyOpts(opts)
+	}
+	db, err := OpenOptimisticTransactionDb(opts, dir)
+	ensure.Nil(t, err)
+
+	return db
+}
@@ -53,11 +53,12 @@ type InfoLogLevel uint
 
 // Log leves.
 const (
-	DebugInfoLogLevel = InfoLogLevel(0)
-	InfoInfoLogLevel  = InfoLogLevel(1)
-	WarnInfoLogLevel  = InfoLogLevel(2)
-	ErrorInfoLogLevel = InfoLogLevel(3)
-	FatalInfoLogLevel = InfoLogLevel(4)
+	DebugInfoLogLevel  = InfoLogLevel(0)
+	InfoInfoLogLevel   = InfoLogLevel(1)
+	WarnInfoLogLevel   = InfoLogLevel(2)
+	ErrorInfoLogLevel  = InfoLogLevel(3)
+	FatalInfoLogLevel  = InfoLogLevel(4)
+	HeaderInfoLogLevel = InfoLogLevel(5)
 )
 
 type WALRecoveryMode int
@@ -77,11 +78,16 @@ type Options struct {
 	env  *Env
 	bbto *BlockBasedTableOptions
 
+	// cmp is the comparator set with SetComparator, nil for the default
+	// and native comparators.
+	cmp Comparator
+
 	// We keep these so we can free their memory in Destroy.
 	ccmp *C.rocksdb_comparator_t
 	cmo  *C.rocksdb_mergeoperator_t
 	cst  *C.rocksdb_slicetransform_t
 	ccf  *C.rocksdb_compactionfilter_t
+	cecf *C.gorocksdb_compactionfilter_t
 }
 
 // NewDefaultOptions creates the default Options.
@@ -112,7 +118,7 @@ func GetOptionsFromString(base *Options, optStr string) (*Options, error) {
 	C.rocksdb_get_options_from_string(base.c, cOptStr, newOpt.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 
 	return newOpt, nil
@@ -122,9 +128,16 @@ func GetOptionsFromString(base *Options, optStr string) (*Options, error) {
 // Parameters that affect behavior
 
 // SetCompactionFilter sets the specified compaction filter
-// which will be applied on compactions.
+// which will be applied on compactions. The filter may implement
+// ExtendedCompactionFilter to also filter merge operands and skip ranges.
 // Default: nil
 func (opts *Options) SetCompactionFilter(value CompactionFilter) {
+	if _, ok := value.(ExtendedCompactionFilter); ok {
+		idx := registerCompactionFilter(value)
+		opts.cecf = C.gorocksdb_compactionfilter_create_v2(C.uintptr_t(idx))
+		C.gorocksdb_options_set_compactionfilter(opts.c, opts.cecf)
+		return
+	}
 	if nc, ok := value.(nativeCompactionFilter); ok {
 		opts.ccf = nc.c
 	} else {
@@ -134,12 +147,38 @@ func (opts *Options) SetCompactionFilter(value CompactionFilter) {
 	C.rocksdb_options_set_compaction_filter(opts.c, opts.ccf)
 }
 
+// SetCompactionFilterFactory sets the factory that provides compaction filter
+// objects which allow an application to modify/delete a key-value during
+// background compaction.
+//
+// A new filter will be created on each compaction run. Each created
+// CompactionFilter will only be used from a single thread and so does not
+// need to be thread-safe.
+//
+// SetCompactionFilter takes precedence if both are set.
+// Default: nil
+func (opts *Options) SetCompactionFilterFactory(value CompactionFilterFactory) {
+	idx := registerCompactionFilterFactory(value)
+	C.gorocksdb_options_set_compactionfilterfactory(opts.c, C.uintptr_t(idx))
+}
+
+// AddTablePropertiesCollectorFactory adds a factory of collectors for user
+// defined table properties. A new collector is created for each table
+// written by a flush or compaction.
+// Default: none
+func (opts *Options) AddTablePropertiesCollectorFactory(value TablePropertiesCollectorFactory) {
+	idx := registerTablePropertiesCollectorFactory(value)
+	C.gorocksdb_options_add_tablepropertiescollectorfactory(opts.c, C.uintptr_t(idx))
+}
+
 // SetComparator sets the comparator which define the order of keys in the table.
 // Default: a comparator that uses lexicographic byte-wise ordering
 func (opts *Options) SetComparator(value Comparator) {
+	opts.cmp = nil
 	if nc, ok := value.(nativeComparator); ok {
 		opts.ccmp = nc.c
 	} else {
+		opts.cmp = value
 		idx := registerComperator(value)
 		opts.ccmp = C.gorocksdb_comparator_create(C.uintptr_t(idx))
 	}
@@ -179,17 +218,6 @@ func (opts *Options) SetMergeOperator(value MergeOperator) {
 //	C.rocksdb_options_set_compaction_filter(opts.c, value.filter)
 //}
 
-// This is a factory that provides compaction filter objects which allow
-// an application to modify/delete a key-value during background compaction.
-//
-// A new filter will be created on each compaction run.  If multithreaded
-// compaction is being used, each created CompactionFilter will only be used
-// from a single thread and so does not need to be thread-safe.
-//
-// Default: a factory that doesn't provide any object
-// std::shared_ptr<CompactionFilterFactory> compaction_filter_factory;
-// TODO: implement in C and Go
-
 // Version TWO of the compaction_filter_factory
 // It supports rolling compaction
 //
@@ -268,6 +296,10 @@ func (opts *Options) SetDBPaths(dbpaths []*DBPath) {
 func (opts *Options) SetEnv(value *Env) {
 	opts.env = value
 
+	if value.ext != nil {
+		C.gorocksdb_options_set_env(opts.c, value.ext)
+		return
+	}
 	C.rocksdb_options_set_env(opts.c, value.c)
 }
 
@@ -1207,6 +1239,9 @@ func (opts *Options) Destroy() {
 	if opts.ccf != nil {
 		C.rocksdb_compactionfilter_destroy(opts.ccf)
 	}
+	if opts.cecf != nil {
+		C.gorocksdb_compactionfilter_destroy(opts.cecf)
+	}
 	opts.c = nil
 	opts.env = nil
 	opts.bbto = nil
@@ -0,0 +1,84 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import "unsafe"
+
+// BackupableDBOptions represent the options of a backup engine,
+// see OpenBackupEngineWithOptions.
+type BackupableDBOptions struct {
+	c *C.rocksdb_backup_engine_options_t
+}
+
+// NewBackupableDBOptions creates the options for a backup engine which
+// stores its backups in backupDir.
+func NewBackupableDBOptions(backupDir string) *BackupableDBOptions {
+	cBackupDir := C.CString(backupDir)
+	defer C.free(unsafe.Pointer(cBackupDir))
+	return &BackupableDBOptions{
+		c: C.rocksdb_backup_engine_options_create(cBackupDir),
+	}
+}
+
+// SetShareTableFiles sets if table files are shared between backups,
+// which makes backups incremental. If false, each backup is a full copy.
+// Default: true
+func (opts *BackupableDBOptions) SetShareTableFiles(value bool) {
+	C.rocksdb_backup_engine_options_set_share_table_files(opts.c, boolToChar(value))
+}
+
+// SetSync sets if files are synced to disk while creating a backup.
+// If false, a backup may be corrupted by a machine crash.
+// Default: true
+func (opts *BackupableDBOptions) SetSync(value bool) {
+	C.rocksdb_backup_engine_options_set_sync(opts.c, boolToChar(value))
+}
+
+// SetDestroyOldData sets if all existing backups are deleted when the
+// backup engine is opened.
+// Default: false
+func (opts *BackupableDBOptions) SetDestroyOldData(value bool) {
+	C.rocksdb_backup_engine_options_set_destroy_old_data(opts.c, boolToChar(value))
+}
+
+// SetBackupLogFiles sets if the WAL files are backed up. If false, the
+// database should be flushed before creating a backup.
+// Default: true
+func (opts *BackupableDBOptions) SetBackupLogFiles(value bool) {
+	C.rocksdb_backup_engine_options_set_backup_log_files(opts.c, boolToChar(value))
+}
+
+// SetBackupRateLimit sets the maximum number of bytes per second
+// written while creating a backup. 0 means unlimited.
+// Default: 0
+func (opts *BackupableDBOptions) SetBackupRateLimit(bytesPerSecond uint64) {
+	C.rocksdb_backup_engine_options_set_backup_rate_limit(opts.c, C.uint64_t(bytesPerSecond))
+}
+
+// SetRestoreRateLimit sets the maximum number of bytes per second
+// written while restoring a backup. 0 means unlimited.
+// Default: 0
+func (opts *BackupableDBOptions) SetRestoreRateLimit(bytesPerSecond uint64) {
+	C.rocksdb_backup_engine_options_set_restore_rate_limit(opts.c, C.uint64_t(bytesPerSecond))
+}
+
+// SetMaxBackgroundOperations sets the number of threads used to copy
+// files while creating and restoring backups.
+// Default: 1
+func (opts *BackupableDBOptions) SetMaxBackgroundOperations(value int) {
+	C.rocksdb_backup_engine_options_set_max_background_operations(opts.c, C.int(value))
+}
+
+// SetMaxValidBackupsToOpen sets the maximum number of the most recent
+// backups which are opened and verified when the backup engine is opened.
+// Default: all backups
+func (opts *BackupableDBOptions) SetMaxValidBackupsToOpen(value int) {
+	C.rocksdb_backup_engine_options_set_max_valid_backups_to_open(opts.c, C.int(value))
+}
+
+// Destroy deallocates the BackupableDBOptions object.
+func (opts *BackupableDBOptions) Destroy() {
+	C.rocksdb_backup_engine_options_destroy(opts.c)
+	opts.c = nil
+}
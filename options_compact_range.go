@@ -0,0 +1,89 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+// BottommostLevelCompaction controls how CompactRange handles the
+// bottommost level.
+type BottommostLevelCompaction uint
+
+const (
+	// BottommostLevelCompactionSkip skips the bottommost level.
+	BottommostLevelCompactionSkip = BottommostLevelCompaction(0)
+	// BottommostLevelCompactionIfHaveCompactionFilter compacts the
+	// bottommost level only if a compaction filter is set.
+	BottommostLevelCompactionIfHaveCompactionFilter = BottommostLevelCompaction(1)
+	// BottommostLevelCompactionForce always compacts the bottommost level.
+	BottommostLevelCompactionForce = BottommostLevelCompaction(2)
+	// BottommostLevelCompactionForceOptimized always compacts the
+	// bottommost level, but skips files created by this compaction.
+	BottommostLevelCompactionForceOptimized = BottommostLevelCompaction(3)
+)
+
+// CompactRangeOptions represent all of the available options for a manual
+// compaction with CompactRangeWithOptions.
+type CompactRangeOptions struct {
+	c *C.rocksdb_compactoptions_t
+}
+
+// NewCompactRangeOptions creates a default CompactRangeOptions object.
+func NewCompactRangeOptions() *CompactRangeOptions {
+	return NewNativeCompactRangeOptions(C.rocksdb_compactoptions_create())
+}
+
+// NewNativeCompactRangeOptions creates a CompactRangeOptions object.
+func NewNativeCompactRangeOptions(c *C.rocksdb_compactoptions_t) *CompactRangeOptions {
+	return &CompactRangeOptions{c}
+}
+
+// SetExclusiveManualCompaction specifies if the compaction runs exclusively,
+// i.e. no other compaction runs at the same time.
+// Default: true
+func (opts *CompactRangeOptions) SetExclusiveManualCompaction(value bool) {
+	C.rocksdb_compactoptions_set_exclusive_manual_compaction(opts.c, boolToChar(value))
+}
+
+// SetBottommostLevelCompaction specifies how the bottommost level is
+// compacted.
+// Default: BottommostLevelCompactionIfHaveCompactionFilter
+func (opts *CompactRangeOptions) SetBottommostLevelCompaction(value BottommostLevelCompaction) {
+	C.rocksdb_compactoptions_set_bottommost_level_compaction(opts.c, C.uchar(value))
+}
+
+// SetChangeLevel specifies if the compacted files are moved to the minimum
+// level capable of holding the data, or to the level set with
+// SetTargetLevel.
+// Default: false
+func (opts *CompactRangeOptions) SetChangeLevel(value bool) {
+	C.rocksdb_compactoptions_set_change_level(opts.c, boolToChar(value))
+}
+
+// SetTargetLevel specifies the level the compacted files are moved to if
+// SetChangeLevel is enabled. A negative level means the minimum level
+// capable of holding the data.
+// Default: -1
+func (opts *CompactRangeOptions) SetTargetLevel(value int) {
+	C.rocksdb_compactoptions_set_target_level(opts.c, C.int(value))
+}
+
+// SetAllowWriteStall specifies if the compaction starts immediately even if
+// it causes writes to stall. Otherwise it waits until it can run without
+// stalling writes.
+// Default: false
+func (opts *CompactRangeOptions) SetAllowWriteStall(value bool) {
+	C.gorocksdb_compactoptions_set_allow_write_stall(opts.c, boolToChar(value))
+}
+
+// SetMaxSubcompactions specifies the maximum number of threads a compaction
+// job may be split into. 0 means the value of the database options.
+// Default: 0
+func (opts *CompactRangeOptions) SetMaxSubcompactions(value uint32) {
+	C.gorocksdb_compactoptions_set_max_subcompactions(opts.c, C.uint32_t(value))
+}
+
+// Destroy deallocates the CompactRangeOptions object.
+func (opts *CompactRangeOptions) Destroy() {
+	C.rocksdb_compactoptions_destroy(opts.c)
+	opts.c = nil
+}
@@ -1,8 +1,16 @@
 package gorocksdb
 
+// #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
-import "unsafe"
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
 
 // ReadTier controls fetching of data during a read request.
 // An application can issue a read request (via Get/Iterators) and specify
@@ -24,6 +32,16 @@ const (
 // database.
 type ReadOptions struct {
 	c *C.rocksdb_readoptions_t
+
+	// RocksDB keeps pointers to the bounds, so they are copied to C memory
+	// which lives as long as the options.
+	cLowerBound *C.char
+	cUpperBound *C.char
+	lowerBound  []byte
+	upperBound  []byte
+
+	// index of the table filter in tableFilters, 0 if none is set
+	tableFilter int
 }
 
 // NewDefaultReadOptions creates a default ReadOptions object.
@@ -33,7 +51,7 @@ func NewDefaultReadOptions() *ReadOptions {
 
 // NewNativeReadOptions creates a ReadOptions object.
 func NewNativeReadOptions(c *C.rocksdb_readoptions_t) *ReadOptions {
-	return &ReadOptions{c}
+	return &ReadOptions{c: c}
 }
 
 // UnsafeGetReadOptions returns the underlying c read options object.
@@ -100,13 +118,167 @@ func (opts *ReadOptions) SetTailing(value bool) {
 // not a valid entry.  If iterator_extractor is not null, the Seek target
 // and iterator_upper_bound need to have the same prefix.
 // This is because ordering is not guaranteed outside of prefix domain.
-// There is no lower bound on the iterator. If needed, that can be easily
-// implemented.
+// The key is copied, an empty key removes the bound.
 // Default: nullptr
 func (opts *ReadOptions) SetIterateUpperBound(key []byte) {
-	cKey := byteToChar(key)
-	cKeyLen := C.size_t(len(key))
-	C.rocksdb_readoptions_set_iterate_upper_bound(opts.c, cKey, cKeyLen)
+	C.free(unsafe.Pointer(opts.cUpperBound))
+	opts.cUpperBound, opts.upperBound = copyBound(key)
+	C.rocksdb_readoptions_set_iterate_upper_bound(opts.c, opts.cUpperBound, C.size_t(len(opts.upperBound)))
+}
+
+// SetIterateLowerBound specifies "iterate_lower_bound", which defines
+// the smallest key at which the backward iterator can return an entry.
+// Once the bound is passed, Valid() will be false.
+// "iterate_lower_bound" is inclusive ie the bound value is a valid entry.
+// If prefix_extractor is not null, the Seek target and iterate_lower_bound
+// need to have the same prefix.
+// The key is copied, an empty key removes the bound.
+// Default: nullptr
+func (opts *ReadOptions) SetIterateLowerBound(key []byte) {
+	C.free(unsafe.Pointer(opts.cLowerBound))
+	opts.cLowerBound, opts.lowerBound = copyBound(key)
+	C.rocksdb_readoptions_set_iterate_lower_bound(opts.c, opts.cLowerBound, C.size_t(len(opts.lowerBound)))
+}
+
+func copyBound(key []byte) (*C.char, []byte) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	return (*C.char)(C.CBytes(key)), append([]byte(nil), key...)
+}
+
+// withBounds returns a copy of the options whose iterate bounds are
+// narrowed to [lower, upper). A nil lower or upper keeps the bound of opts.
+// The bounds are compared byte-wise. The copy owns its bounds and table
+// filter, so it stays valid when opts is destroyed. It must be destroyed
+// after the iterators created with it are closed.
+func (opts *ReadOptions) withBounds(lower, upper []byte) *ReadOptions {
+	bounded := NewNativeReadOptions(C.gorocksdb_readoptions_copy(opts.c))
+	if lower == nil || (opts.lowerBound != nil && bytes.Compare(lower, opts.lowerBound) <= 0) {
+		lower = opts.lowerBound
+	}
+	if upper == nil || (opts.upperBound != nil && bytes.Compare(upper, opts.upperBound) >= 0) {
+		upper = opts.upperBound
+	}
+	// the copied options still point to the bounds and the table filter
+	// of opts, replace them with ones of the copy
+	bounded.SetIterateLowerBound(lower)
+	bounded.SetIterateUpperBound(upper)
+	if filter, ok := loadTableFilter(opts.tableFilter); ok {
+		bounded.SetTableFilter(filter)
+	}
+	return bounded
+}
+
+// SetTotalOrderSeek enables a total order seek regardless of the index
+// format (e.g. hash index) used in the table. Some table formats (e.g.
+// plain table) may not support this option.
+// If true when calling Get(), the prefix bloom filter is skipped when
+// reading from block based tables.
+// Default: false
+func (opts *ReadOptions) SetTotalOrderSeek(value bool) {
+	C.rocksdb_readoptions_set_total_order_seek(opts.c, boolToChar(value))
+}
+
+// SetAutoPrefixMode specifies whether the seek mode is selected
+// automatically. If true, the iterator uses the prefix bloom filter only
+// when the seek key and the upper bound share the same prefix, and a total
+// order seek otherwise. The results are the same as with SetTotalOrderSeek.
+// Default: false
+func (opts *ReadOptions) SetAutoPrefixMode(value bool) {
+	C.gorocksdb_readoptions_set_auto_prefix_mode(opts.c, boolToChar(value))
+}
+
+// SetIgnoreRangeDeletions specifies whether keys deleted using DeleteRange
+// are ignored and may be returned by reads. Enabling it improves read
+// performance in databases which do not use range deletions.
+// Default: false
+func (opts *ReadOptions) SetIgnoreRangeDeletions(value bool) {
+	C.rocksdb_readoptions_set_ignore_range_deletions(opts.c, boolToChar(value))
+}
+
+// SetMaxSkippableInternalKeys specifies the number of internal keys, e.g.
+// deleted or overwritten ones, an iterator may skip during a single
+// operation. Once the threshold is reached, the iterator stops and its
+// status becomes Incomplete.
+// Default: 0 (unlimited)
+func (opts *ReadOptions) SetMaxSkippableInternalKeys(value uint64) {
+	C.rocksdb_readoptions_set_max_skippable_internal_keys(opts.c, C.uint64_t(value))
+}
+
+// SetBackgroundPurgeOnIteratorCleanup specifies whether obsolete files
+// are deleted in a background job when an iterator is closed, instead of
+// in the thread closing it.
+// Default: false
+func (opts *ReadOptions) SetBackgroundPurgeOnIteratorCleanup(value bool) {
+	C.rocksdb_readoptions_set_background_purge_on_iterator_cleanup(opts.c, boolToChar(value))
+}
+
+// SetDeadline specifies the time after which Get and MultiGet give up
+// and return a TimedOut error. The deadline is checked on a best effort
+// basis, so a read may take longer.
+// Default: zero time (no deadline)
+func (opts *ReadOptions) SetDeadline(deadline time.Time) {
+	var micros uint64
+	if !deadline.IsZero() {
+		micros = uint64(deadline.UnixNano() / int64(time.Microsecond))
+	}
+	C.rocksdb_readoptions_set_deadline(opts.c, C.uint64_t(micros))
+}
+
+// SetIOTimeout specifies the timeout of each file read done by Get,
+// MultiGet and iterators. A read exceeding it fails with a TimedOut error.
+// Default: 0 (no timeout)
+func (opts *ReadOptions) SetIOTimeout(timeout time.Duration) {
+	C.rocksdb_readoptions_set_io_timeout(opts.c, C.uint64_t(timeout/time.Microsecond))
+}
+
+// SetTableFilter sets a callback which decides by the properties of an
+// SST file whether it is read. Files for which filter returns false are
+// skipped by iterators. The callback is called from the reading thread
+// and must be thread-safe. It is released when the options are destroyed,
+// so the options must outlive the iterators created with them.
+// Default: nil
+func (opts *ReadOptions) SetTableFilter(filter func(props *TableProperties) bool) {
+	tableFilters.Delete(opts.tableFilter)
+	opts.tableFilter = 0
+	if filter == nil {
+		C.gorocksdb_readoptions_set_table_filter(opts.c, 0, 0)
+		return
+	}
+	opts.tableFilter = registerTableFilter(filter)
+	C.gorocksdb_readoptions_set_table_filter(opts.c, C.uintptr_t(opts.tableFilter), 1)
+}
+
+// Hold references to the table filters of read options. Entries are
+// removed again when the options are destroyed or get another filter.
+var (
+	tableFilters   sync.Map
+	tableFilterSeq int64
+)
+
+func registerTableFilter(filter func(props *TableProperties) bool) int {
+	// 0 is reserved for "no filter"
+	idx := int(atomic.AddInt64(&tableFilterSeq, 1))
+	tableFilters.Store(idx, filter)
+	return idx
+}
+
+func loadTableFilter(idx int) (func(props *TableProperties) bool, bool) {
+	v, ok := tableFilters.Load(idx)
+	if !ok {
+		return nil, false
+	}
+	return v.(func(props *TableProperties) bool), true
+}
+
+//export gorocksdb_readoptions_table_filter
+func gorocksdb_readoptions_table_filter(idx int, cProps *C.gorocksdb_tableproperties_t) C.uchar {
+	filter, ok := loadTableFilter(idx)
+	if !ok {
+		return boolToChar(true)
+	}
+	return boolToChar(filter(newTableProperties(cProps)))
 }
 
 // SetPinData specifies the value of "pin_data". If true, it keeps the blocks
@@ -132,5 +304,11 @@ func (opts *ReadOptions) SetReadaheadSize(value uint64) {
 // Destroy deallocates the ReadOptions object.
 func (opts *ReadOptions) Destroy() {
 	C.rocksdb_readoptions_destroy(opts.c)
+	C.free(unsafe.Pointer(opts.cLowerBound))
+	C.free(unsafe.Pointer(opts.cUpperBound))
+	tableFilters.Delete(opts.tableFilter)
 	opts.c = nil
+	opts.cLowerBound = nil
+	opts.cUpperBound = nil
+	opts.tableFilter = 0
 }
@@ -61,6 +61,17 @@ type PinnableSliceHandle struct {
 	c *C.rocksdb_pinnableslice_t
 }
 
+// PinnableSliceHandles is a list of PinnableSliceHandles, as returned by
+// DB.BatchedMultiGet.
+type PinnableSliceHandles []*PinnableSliceHandle
+
+// Destroy calls Destroy on every handle of the list.
+func (handles PinnableSliceHandles) Destroy() {
+	for _, h := range handles {
+		h.Destroy()
+	}
+}
+
 // NewNativePinnableSliceHandle creates a PinnableSliceHandle object.
 func NewNativePinnableSliceHandle(c *C.rocksdb_pinnableslice_t) *PinnableSliceHandle {
 	return &PinnableSliceHandle{c}
@@ -78,6 +89,11 @@ func (h *PinnableSliceHandle) Data() []byte {
 	return charToByte(cValue, cValLen)
 }
 
+// Exists returns if the key exists
+func (h *PinnableSliceHandle) Exists() bool {
+	return h.c != nil
+}
+
 // Destroy calls the destructor of the underlying pinnable slice handle.
 func (h *PinnableSliceHandle) Destroy() {
 	C.rocksdb_pinnableslice_destroy(h.c)
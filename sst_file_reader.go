@@ -0,0 +1,86 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+import "unsafe"
+
+// SSTFileReader is used to read sst files, e.g. those created by an
+// SSTFileWriter, without opening a database.
+type SSTFileReader struct {
+	c *C.gorocksdb_sstfilereader_t
+}
+
+// NewSSTFileReader creates an SSTFileReader object. The options must use the
+// same comparator the file was written with.
+func NewSSTFileReader(opts *Options) *SSTFileReader {
+	c := C.gorocksdb_sstfilereader_create(opts.c)
+	return &SSTFileReader{c: c}
+}
+
+// Open prepares the SSTFileReader to read the file located at "path".
+func (r *SSTFileReader) Open(path string) error {
+	var (
+		cErr  *C.char
+		cPath = C.CString(path)
+	)
+	defer C.free(unsafe.Pointer(cPath))
+	C.gorocksdb_sstfilereader_open(r.c, cPath, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// NewIterator returns an Iterator over the contents of the opened file.
+// The iterator must be closed before the reader is destroyed.
+func (r *SSTFileReader) NewIterator(opts *ReadOptions) *Iterator {
+	return NewNativeIterator(
+		unsafe.Pointer(C.gorocksdb_sstfilereader_new_iterator(r.c, opts.c)))
+}
+
+// VerifyChecksum verifies the checksums of all blocks in the opened file.
+func (r *SSTFileReader) VerifyChecksum() error {
+	var cErr *C.char
+	C.gorocksdb_sstfilereader_verify_checksum(r.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// GetTableProperties returns the table properties of the opened file.
+func (r *SSTFileReader) GetTableProperties() *TableProperties {
+	var cProps C.gorocksdb_tableproperties_t
+	C.gorocksdb_sstfilereader_get_table_properties(r.c, &cProps)
+	return newTableProperties(&cProps)
+}
+
+// KeyRange returns the smallest and largest key of the opened file.
+// Both are nil if the file contains no keys.
+func (r *SSTFileReader) KeyRange() (smallest, largest []byte, err error) {
+	opts := NewDefaultReadOptions()
+	defer opts.Destroy()
+	it := r.NewIterator(opts)
+	defer it.Close()
+
+	it.SeekToFirst()
+	if it.Valid() {
+		smallest = append([]byte(nil), it.Key().Data()...)
+	}
+	it.SeekToLast()
+	if it.Valid() {
+		largest = append([]byte(nil), it.Key().Data()...)
+	}
+	return smallest, largest, it.Err()
+}
+
+// Destroy destroys the SSTFileReader object.
+func (r *SSTFileReader) Destroy() {
+	C.gorocksdb_sstfilereader_destroy(r.c)
+	r.c = nil
+}
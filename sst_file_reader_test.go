@@ -0,0 +1,67 @@
+package gorocksdb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSSTFileReader(t *testing.T) {
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+
+	filePath, err := ioutil.TempFile("", "sst-file-reader-test")
+	ensure.Nil(t, err)
+	defer os.Remove(filePath.Name())
+
+	envOpts := NewDefaultEnvOptions()
+	w := NewSSTFileWriter(envOpts, opts)
+	defer w.Destroy()
+	ensure.Nil(t, w.Open(filePath.Name()))
+	ensure.Nil(t, w.Add([]byte("aaa"), []byte("aaaValue")))
+	ensure.Nil(t, w.Add([]byte("bbb"), []byte("bbbValue")))
+	ensure.Nil(t, w.Add([]byte("ccc"), []byte("cccValue")))
+	ensure.Nil(t, w.Finish())
+
+	r := NewSSTFileReader(opts)
+	defer r.Destroy()
+	ensure.Nil(t, r.Open(filePath.Name()))
+	ensure.Nil(t, r.VerifyChecksum())
+
+	props := r.GetTableProperties()
+	ensure.DeepEqual(t, props.NumEntries, uint64(3))
+	ensure.DeepEqual(t, props.NumDeletions, uint64(0))
+	ensure.True(t, props.CompressionName != "")
+
+	smallest, largest, err := r.KeyRange()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, smallest, []byte("aaa"))
+	ensure.DeepEqual(t, largest, []byte("ccc"))
+
+	ro := NewDefaultReadOptions()
+	it := r.NewIterator(ro)
+	defer it.Close()
+	var values []string
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		values = append(values, string(it.Value().Data()))
+	}
+	ensure.Nil(t, it.Err())
+	ensure.DeepEqual(t, values, []string{"aaaValue", "bbbValue", "cccValue"})
+}
+
+func TestSSTFileReaderInvalidFile(t *testing.T) {
+	filePath, err := ioutil.TempFile("", "sst-file-reader-test")
+	ensure.Nil(t, err)
+	defer os.Remove(filePath.Name())
+	_, err = filePath.WriteString("not an sst file")
+	ensure.Nil(t, err)
+	ensure.Nil(t, filePath.Close())
+
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	r := NewSSTFileReader(opts)
+	defer r.Destroy()
+	ensure.NotNil(t, r.Open(filePath.Name()))
+}
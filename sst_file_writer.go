@@ -2,12 +2,10 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
-import (
-	"errors"
-	"unsafe"
-)
+import "unsafe"
 
 // SSTFileWriter is used to create sst files that can be added to database later.
 // All keys in files generated by SstFileWriter will have sequence number = 0.
@@ -31,13 +29,35 @@ func (w *SSTFileWriter) Open(path string) error {
 	C.rocksdb_sstfilewriter_open(w.c, cPath, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
 
+// ExternalSstFileInfo describes an sst file created by an SSTFileWriter.
+type ExternalSstFileInfo struct {
+	FilePath string
+
+	// SmallestKey and LargestKey are the smallest and largest point keys
+	// in the file; SmallestRangeDelKey and LargestRangeDelKey are the
+	// smallest and largest keys covered by range deletions. They are
+	// empty if the file has no such entries.
+	SmallestKey         []byte
+	LargestKey          []byte
+	SmallestRangeDelKey []byte
+	LargestRangeDelKey  []byte
+
+	SequenceNumber     uint64
+	FileSize           uint64
+	NumEntries         uint64
+	NumRangeDelEntries uint64
+	Version            int
+}
+
 // Add adds key, value to currently opened file.
 // REQUIRES: key is after any previously added key according to comparator.
+//
+// Deprecated: Use Put instead.
 func (w *SSTFileWriter) Add(key, value []byte) error {
 	cKey := byteToChar(key)
 	cValue := byteToChar(value)
@@ -45,22 +65,112 @@ func (w *SSTFileWriter) Add(key, value []byte) error {
 	C.rocksdb_sstfilewriter_add(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Put adds key, value to currently opened file.
+// REQUIRES: key is after any previously added key according to comparator.
+func (w *SSTFileWriter) Put(key, value []byte) error {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	var cErr *C.char
+	C.rocksdb_sstfilewriter_put(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Merge adds a merge operand for key to currently opened file.
+// REQUIRES: key is after any previously added key according to comparator.
+func (w *SSTFileWriter) Merge(key, value []byte) error {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	var cErr *C.char
+	C.rocksdb_sstfilewriter_merge(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Delete adds a deletion of key to currently opened file.
+// REQUIRES: key is after any previously added key according to comparator.
+func (w *SSTFileWriter) Delete(key []byte) error {
+	cKey := byteToChar(key)
+	var cErr *C.char
+	C.rocksdb_sstfilewriter_delete(w.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
 
+// DeleteRange adds a deletion of the keys in [startKey, endKey) to currently
+// opened file. Unlike the other entries, range deletions may be added in
+// any order.
+func (w *SSTFileWriter) DeleteRange(startKey, endKey []byte) error {
+	cStartKey := byteToChar(startKey)
+	cEndKey := byteToChar(endKey)
+	var cErr *C.char
+	C.rocksdb_sstfilewriter_delete_range(w.c, cStartKey, C.size_t(len(startKey)), cEndKey, C.size_t(len(endKey)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// FileSize returns the current size of the file being written.
+func (w *SSTFileWriter) FileSize() uint64 {
+	var cSize C.uint64_t
+	C.rocksdb_sstfilewriter_file_size(w.c, &cSize)
+	return uint64(cSize)
+}
+
 // Finish finishes writing to sst file and close file.
 func (w *SSTFileWriter) Finish() error {
 	var cErr *C.char
 	C.rocksdb_sstfilewriter_finish(w.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
 
+// FinishWithInfo finishes writing to sst file and close file like Finish.
+// It returns the information about the created file.
+func (w *SSTFileWriter) FinishWithInfo() (*ExternalSstFileInfo, error) {
+	var (
+		cErr  *C.char
+		cInfo C.gorocksdb_externalsstfileinfo_t
+	)
+	C.gorocksdb_sstfilewriter_finish(w.c, &cInfo, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	defer C.gorocksdb_externalsstfileinfo_destroy(&cInfo)
+	return &ExternalSstFileInfo{
+		FilePath:            C.GoString(cInfo.file_path),
+		SmallestKey:         C.GoBytes(unsafe.Pointer(cInfo.smallest_key), C.int(cInfo.smallest_key_len)),
+		LargestKey:          C.GoBytes(unsafe.Pointer(cInfo.largest_key), C.int(cInfo.largest_key_len)),
+		SmallestRangeDelKey: C.GoBytes(unsafe.Pointer(cInfo.smallest_range_del_key), C.int(cInfo.smallest_range_del_key_len)),
+		LargestRangeDelKey:  C.GoBytes(unsafe.Pointer(cInfo.largest_range_del_key), C.int(cInfo.largest_range_del_key_len)),
+		SequenceNumber:      uint64(cInfo.sequence_number),
+		FileSize:            uint64(cInfo.file_size),
+		NumEntries:          uint64(cInfo.num_entries),
+		NumRangeDelEntries:  uint64(cInfo.num_range_del_entries),
+		Version:             int(cInfo.version),
+	}, nil
+}
+
 // Destroy destroys the SSTFileWriter object.
 func (w *SSTFileWriter) Destroy() {
 	C.rocksdb_sstfilewriter_destroy(w.c)
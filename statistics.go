@@ -0,0 +1,243 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"strconv"
+	"unsafe"
+)
+
+// Ticker identifies a statistics counter. The values are stable across
+// RocksDB releases and must match gorocksdb_tickers in gorocksdb_ext.cc.
+type Ticker int
+
+// Tickers.
+const (
+	// TickerBlockCacheMiss is the total number of block cache misses.
+	TickerBlockCacheMiss Ticker = iota
+	// TickerBlockCacheHit is the total number of block cache hits.
+	TickerBlockCacheHit
+	// TickerBlockCacheAdd is the number of blocks added to the block cache.
+	TickerBlockCacheAdd
+	// TickerBlockCacheAddFailures is the number of failures when adding blocks to the block cache.
+	TickerBlockCacheAddFailures
+	// TickerBlockCacheIndexMiss is the number of times the cache missed when accessing an index block.
+	TickerBlockCacheIndexMiss
+	// TickerBlockCacheIndexHit is the number of times the cache hit when accessing an index block.
+	TickerBlockCacheIndexHit
+	// TickerBlockCacheFilterMiss is the number of times the cache missed when accessing a filter block.
+	TickerBlockCacheFilterMiss
+	// TickerBlockCacheFilterHit is the number of times the cache hit when accessing a filter block.
+	TickerBlockCacheFilterHit
+	// TickerBlockCacheDataMiss is the number of times the cache missed when accessing a data block.
+	TickerBlockCacheDataMiss
+	// TickerBlockCacheDataHit is the number of times the cache hit when accessing a data block.
+	TickerBlockCacheDataHit
+	// TickerBlockCacheBytesRead is the number of bytes read from the block cache.
+	TickerBlockCacheBytesRead
+	// TickerBlockCacheBytesWrite is the number of bytes written into the block cache.
+	TickerBlockCacheBytesWrite
+	// TickerBloomFilterUseful is the number of times a bloom filter avoided a file read.
+	TickerBloomFilterUseful
+	// TickerMemtableHit is the number of reads served by a memtable.
+	TickerMemtableHit
+	// TickerMemtableMiss is the number of reads not served by a memtable.
+	TickerMemtableMiss
+	// TickerGetHitL0 is the number of reads served by level 0.
+	TickerGetHitL0
+	// TickerGetHitL1 is the number of reads served by level 1.
+	TickerGetHitL1
+	// TickerGetHitL2AndUp is the number of reads served by level 2 and up.
+	TickerGetHitL2AndUp
+	// TickerCompactionKeyDropNewerEntry is the number of keys dropped by
+	// compaction because a newer entry for the key exists.
+	TickerCompactionKeyDropNewerEntry
+	// TickerCompactionKeyDropObsolete is the number of keys dropped by
+	// compaction because they were deleted or expired.
+	TickerCompactionKeyDropObsolete
+	// TickerCompactionKeyDropRangeDel is the number of keys dropped by
+	// compaction because they were covered by a range deletion.
+	TickerCompactionKeyDropRangeDel
+	// TickerCompactionKeyDropUser is the number of keys dropped by a
+	// compaction filter.
+	TickerCompactionKeyDropUser
+	// TickerNumberKeysWritten is the number of keys written to the database.
+	TickerNumberKeysWritten
+	// TickerNumberKeysRead is the number of keys read from the database.
+	TickerNumberKeysRead
+	// TickerNumberKeysUpdated is the number of keys updated in place.
+	TickerNumberKeysUpdated
+	// TickerBytesWritten is the number of uncompressed bytes written by Put,
+	// Delete, Merge and Write.
+	TickerBytesWritten
+	// TickerBytesRead is the number of uncompressed bytes read by Get.
+	TickerBytesRead
+	// TickerNumberDBSeek is the number of calls to Seek and its variants.
+	TickerNumberDBSeek
+	// TickerNumberDBNext is the number of calls to Next.
+	TickerNumberDBNext
+	// TickerNumberDBPrev is the number of calls to Prev.
+	TickerNumberDBPrev
+	// TickerStallMicros is the number of microseconds writes were stalled.
+	TickerStallMicros
+	// TickerWALFileSynced is the number of times the WAL was synced.
+	TickerWALFileSynced
+	// TickerWALFileBytes is the number of bytes written to the WAL.
+	TickerWALFileBytes
+	// TickerCompactReadBytes is the number of bytes read during compaction.
+	TickerCompactReadBytes
+	// TickerCompactWriteBytes is the number of bytes written during compaction.
+	TickerCompactWriteBytes
+	// TickerFlushWriteBytes is the number of bytes written during flush.
+	TickerFlushWriteBytes
+	// TickerNumberMultigetCalls is the number of MultiGet calls.
+	TickerNumberMultigetCalls
+	// TickerNumberMultigetKeysRead is the number of keys read by MultiGet.
+	TickerNumberMultigetKeysRead
+	// TickerNumberMultigetBytesRead is the number of bytes read by MultiGet.
+	TickerNumberMultigetBytesRead
+	// TickerNumberMergeFailures is the number of failed merge operations.
+	TickerNumberMergeFailures
+
+	// numTickers is the number of tickers.
+	numTickers
+)
+
+// String returns the name RocksDB uses for the ticker.
+func (t Ticker) String() string {
+	if t < 0 || t >= numTickers {
+		return "Ticker(" + strconv.Itoa(int(t)) + ")"
+	}
+	return C.GoString(C.gorocksdb_ticker_name(C.int(t)))
+}
+
+// Histogram identifies a statistics histogram. The values are stable across
+// RocksDB releases and must match gorocksdb_histograms in gorocksdb_ext.cc.
+type Histogram int
+
+// Histograms.
+const (
+	// HistogramDBGet measures the latency of Get in microseconds.
+	HistogramDBGet Histogram = iota
+	// HistogramDBWrite measures the latency of writes in microseconds.
+	HistogramDBWrite
+	// HistogramDBMultiGet measures the latency of MultiGet in microseconds.
+	HistogramDBMultiGet
+	// HistogramDBSeek measures the latency of Seek in microseconds.
+	HistogramDBSeek
+	// HistogramCompactionTime measures the duration of compactions in microseconds.
+	HistogramCompactionTime
+	// HistogramFlushTime measures the duration of flushes in microseconds.
+	HistogramFlushTime
+	// HistogramWriteStall measures the duration of write stalls in microseconds.
+	HistogramWriteStall
+	// HistogramWALFileSync measures the latency of WAL syncs in microseconds.
+	HistogramWALFileSync
+	// HistogramTableSync measures the latency of table file syncs in microseconds.
+	HistogramTableSync
+	// HistogramSSTRead measures the latency of SST file reads in microseconds.
+	HistogramSSTRead
+	// HistogramBytesPerRead measures the size of values read by Get.
+	HistogramBytesPerRead
+	// HistogramBytesPerWrite measures the size of writes.
+	HistogramBytesPerWrite
+	// HistogramBytesPerMultiGet measures the size of values read by MultiGet.
+	HistogramBytesPerMultiGet
+
+	// numHistograms is the number of histograms.
+	numHistograms
+)
+
+// String returns the name RocksDB uses for the histogram.
+func (h Histogram) String() string {
+	if h < 0 || h >= numHistograms {
+		return "Histogram(" + strconv.Itoa(int(h)) + ")"
+	}
+	return C.GoString(C.gorocksdb_histogram_name(C.int(h)))
+}
+
+// HistogramData holds the percentiles and totals of a histogram.
+type HistogramData struct {
+	P50     float64
+	P95     float64
+	P99     float64
+	P100    float64
+	Min     float64
+	Average float64
+	StdDev  float64
+	Count   uint64
+	Sum     uint64
+}
+
+// Statistics gives typed access to the statistics collected by a database
+// opened with Options.EnableStatistics.
+type Statistics struct {
+	opts *Options
+}
+
+// GetStatistics returns the statistics collected for the options.
+func (opts *Options) GetStatistics() *Statistics {
+	return &Statistics{opts: opts}
+}
+
+// GetStatistics returns the statistics collected for the database.
+// Statistics must have been enabled on the Options the database was
+// opened with.
+func (db *DB) GetStatistics() *Statistics {
+	return db.opts.GetStatistics()
+}
+
+// GetTickerCount returns the value of the ticker.
+func (s *Statistics) GetTickerCount(ticker Ticker) uint64 {
+	if ticker < 0 || ticker >= numTickers {
+		return 0
+	}
+	return uint64(C.rocksdb_options_statistics_get_ticker_count(s.opts.c, C.gorocksdb_ticker_type(C.int(ticker))))
+}
+
+// GetHistogramData returns the data of the histogram.
+func (s *Statistics) GetHistogramData(histogram Histogram) HistogramData {
+	if histogram < 0 || histogram >= numHistograms {
+		return HistogramData{}
+	}
+	cData := C.rocksdb_statistics_histogram_data_create()
+	defer C.rocksdb_statistics_histogram_data_destroy(cData)
+	C.rocksdb_options_statistics_get_histogram_data(s.opts.c, C.gorocksdb_histogram_type(C.int(histogram)), cData)
+	return HistogramData{
+		P50:     float64(C.rocksdb_statistics_histogram_data_get_median(cData)),
+		P95:     float64(C.rocksdb_statistics_histogram_data_get_p95(cData)),
+		P99:     float64(C.rocksdb_statistics_histogram_data_get_p99(cData)),
+		P100:    float64(C.rocksdb_statistics_histogram_data_get_max(cData)),
+		Min:     float64(C.rocksdb_statistics_histogram_data_get_min(cData)),
+		Average: float64(C.rocksdb_statistics_histogram_data_get_average(cData)),
+		StdDev:  float64(C.rocksdb_statistics_histogram_data_get_std_dev(cData)),
+		Count:   uint64(C.rocksdb_statistics_histogram_data_get_count(cData)),
+		Sum:     uint64(C.rocksdb_statistics_histogram_data_get_sum(cData)),
+	}
+}
+
+// Snapshot returns the values of all the tickers and histograms defined in
+// this package.
+func (s *Statistics) Snapshot() (map[Ticker]uint64, map[Histogram]HistogramData) {
+	tickers := make(map[Ticker]uint64, int(numTickers))
+	for t := Ticker(0); t < numTickers; t++ {
+		tickers[t] = s.GetTickerCount(t)
+	}
+	histograms := make(map[Histogram]HistogramData, int(numHistograms))
+	for h := Histogram(0); h < numHistograms; h++ {
+		histograms[h] = s.GetHistogramData(h)
+	}
+	return tickers, histograms
+}
+
+// Reset resets all tickers and histograms to zero.
+func (s *Statistics) Reset() error {
+	var cErr *C.char
+	C.gorocksdb_options_statistics_reset(s.opts.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestStatistics(t *testing.T) {
+	db := newTestDB(t, "TestStatistics", func(opts *Options) {
+		opts.EnableStatistics()
+	})
+	defer db.Close()
+
+	var (
+		givenKey = []byte("hello")
+		givenVal = []byte("world")
+		wo       = NewDefaultWriteOptions()
+		ro       = NewDefaultReadOptions()
+	)
+	ensure.Nil(t, db.Put(wo, givenKey, givenVal))
+	v, err := db.Get(ro, givenKey)
+	ensure.Nil(t, err)
+	v.Free()
+
+	stats := db.GetStatistics()
+	ensure.DeepEqual(t, stats.GetTickerCount(TickerNumberKeysWritten), uint64(1))
+	ensure.DeepEqual(t, stats.GetTickerCount(TickerNumberKeysRead), uint64(1))
+	ensure.DeepEqual(t, stats.GetHistogramData(HistogramDBGet).Count, uint64(1))
+
+	ensure.Nil(t, stats.Reset())
+	ensure.DeepEqual(t, stats.GetTickerCount(TickerNumberKeysWritten), uint64(0))
+	ensure.DeepEqual(t, stats.GetHistogramData(HistogramDBGet).Count, uint64(0))
+
+	ensure.Nil(t, db.Put(wo, givenKey, givenVal))
+	ensure.DeepEqual(t, stats.GetTickerCount(TickerNumberKeysWritten), uint64(1))
+}
+
+func TestStatisticsNames(t *testing.T) {
+	ensure.DeepEqual(t, TickerBlockCacheMiss.String(), "rocksdb.block.cache.miss")
+	ensure.DeepEqual(t, TickerNumberMergeFailures.String(), "rocksdb.number.merge.failures")
+	ensure.DeepEqual(t, HistogramDBGet.String(), "rocksdb.db.get.micros")
+	ensure.DeepEqual(t, Ticker(-1).String(), "Ticker(-1)")
+}
@@ -0,0 +1,156 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"time"
+	"unsafe"
+)
+
+// TableProperties contains the properties of a single SST file.
+type TableProperties struct {
+	DataSize          uint64
+	IndexSize         uint64
+	FilterSize        uint64
+	RawKeySize        uint64
+	RawValueSize      uint64
+	NumDataBlocks     uint64
+	NumEntries        uint64
+	NumDeletions      uint64
+	NumMergeOperands  uint64
+	NumRangeDeletions uint64
+	FormatVersion     uint64
+
+	// CreationTime is the time the oldest data in the file was written,
+	// OldestKeyTime the time the oldest key was inserted and
+	// FileCreationTime the time the file itself was created.
+	// Each is the zero time if unknown.
+	CreationTime     time.Time
+	OldestKeyTime    time.Time
+	FileCreationTime time.Time
+
+	ColumnFamilyName  string
+	ComparatorName    string
+	MergeOperatorName string
+	CompressionName   string
+
+	// UserCollectedProperties are the properties returned by the
+	// TablePropertiesCollectors of the table.
+	UserCollectedProperties map[string]string
+}
+
+// newTableProperties converts the C properties and releases their strings.
+func newTableProperties(c *C.gorocksdb_tableproperties_t) *TableProperties {
+	defer C.gorocksdb_tableproperties_destroy(c)
+	props := &TableProperties{
+		DataSize:          uint64(c.data_size),
+		IndexSize:         uint64(c.index_size),
+		FilterSize:        uint64(c.filter_size),
+		RawKeySize:        uint64(c.raw_key_size),
+		RawValueSize:      uint64(c.raw_value_size),
+		NumDataBlocks:     uint64(c.num_data_blocks),
+		NumEntries:        uint64(c.num_entries),
+		NumDeletions:      uint64(c.num_deletions),
+		NumMergeOperands:  uint64(c.num_merge_operands),
+		NumRangeDeletions: uint64(c.num_range_deletions),
+		FormatVersion:     uint64(c.format_version),
+		CreationTime:      unixTime(uint64(c.creation_time)),
+		OldestKeyTime:     unixTime(uint64(c.oldest_key_time)),
+		FileCreationTime:  unixTime(uint64(c.file_creation_time)),
+		ColumnFamilyName:  C.GoString(c.column_family_name),
+		ComparatorName:    C.GoString(c.comparator_name),
+		MergeOperatorName: C.GoString(c.merge_operator_name),
+		CompressionName:   C.GoString(c.compression_name),
+	}
+	if n := int(c.num_user_collected_properties); n > 0 {
+		keys := (*[(1 << 29) - 1]*C.char)(unsafe.Pointer(c.user_collected_property_keys))[:n:n]
+		keyLens := (*[(1 << 29) - 1]C.size_t)(unsafe.Pointer(c.user_collected_property_key_lens))[:n:n]
+		vals := (*[(1 << 29) - 1]*C.char)(unsafe.Pointer(c.user_collected_property_values))[:n:n]
+		valLens := (*[(1 << 29) - 1]C.size_t)(unsafe.Pointer(c.user_collected_property_value_lens))[:n:n]
+		props.UserCollectedProperties = make(map[string]string, n)
+		for i := 0; i < n; i++ {
+			props.UserCollectedProperties[C.GoStringN(keys[i], C.int(keyLens[i]))] = C.GoStringN(vals[i], C.int(valLens[i]))
+		}
+	}
+	return props
+}
+
+// unixTime converts seconds since the epoch, where 0 means unknown.
+func unixTime(sec uint64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(sec), 0)
+}
+
+// GetPropertiesOfAllTables returns the properties of all tables of the
+// default column family, keyed by file path.
+func (db *DB) GetPropertiesOfAllTables() (map[string]*TableProperties, error) {
+	return db.getPropertiesOfAllTables(nil)
+}
+
+// GetPropertiesOfAllTablesCF returns the properties of all tables of the
+// column family, keyed by file path.
+func (db *DB) GetPropertiesOfAllTablesCF(cf *ColumnFamilyHandle) (map[string]*TableProperties, error) {
+	return db.getPropertiesOfAllTables(cf.c)
+}
+
+func (db *DB) getPropertiesOfAllTables(cCf *C.rocksdb_column_family_handle_t) (map[string]*TableProperties, error) {
+	var cErr *C.char
+	cColl := C.gorocksdb_get_properties_of_all_tables(db.c, cCf, &cErr)
+	return newTablePropertiesCollection(cColl, cErr)
+}
+
+// GetPropertiesOfTablesInRange returns the properties of the tables of the
+// default column family which overlap with any of the ranges, keyed by
+// file path.
+func (db *DB) GetPropertiesOfTablesInRange(ranges []Range) (map[string]*TableProperties, error) {
+	return db.getPropertiesOfTablesInRange(nil, ranges)
+}
+
+// GetPropertiesOfTablesInRangeCF returns the properties of the tables of
+// the column family which overlap with any of the ranges, keyed by file
+// path.
+func (db *DB) GetPropertiesOfTablesInRangeCF(cf *ColumnFamilyHandle, ranges []Range) (map[string]*TableProperties, error) {
+	return db.getPropertiesOfTablesInRange(cf.c, ranges)
+}
+
+func (db *DB) getPropertiesOfTablesInRange(cCf *C.rocksdb_column_family_handle_t, ranges []Range) (map[string]*TableProperties, error) {
+	cStarts := make(charsSlice, len(ranges))
+	cLimits := make(charsSlice, len(ranges))
+	cStartLens := make(sizeTSlice, len(ranges))
+	cLimitLens := make(sizeTSlice, len(ranges))
+	for i, r := range ranges {
+		cStarts[i] = (*C.char)(C.CBytes(r.Start))
+		cStartLens[i] = C.size_t(len(r.Start))
+		cLimits[i] = (*C.char)(C.CBytes(r.Limit))
+		cLimitLens[i] = C.size_t(len(r.Limit))
+	}
+	defer cStarts.Destroy()
+	defer cLimits.Destroy()
+
+	var cErr *C.char
+	cColl := C.gorocksdb_get_properties_of_tables_in_range(db.c, cCf, C.size_t(len(ranges)),
+		cStarts.c(), cStartLens.c(), cLimits.c(), cLimitLens.c(), &cErr)
+	return newTablePropertiesCollection(cColl, cErr)
+}
+
+func newTablePropertiesCollection(cColl *C.gorocksdb_tablepropertiescollection_t, cErr *C.char) (map[string]*TableProperties, error) {
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	defer C.gorocksdb_tablepropertiescollection_destroy(cColl)
+
+	count := int(C.gorocksdb_tablepropertiescollection_count(cColl))
+	props := make(map[string]*TableProperties, count)
+	for i := 0; i < count; i++ {
+		var cProps C.gorocksdb_tableproperties_t
+		C.gorocksdb_tablepropertiescollection_get(cColl, C.size_t(i), &cProps)
+		name := C.GoString(C.gorocksdb_tablepropertiescollection_name(cColl, C.size_t(i)))
+		props[name] = newTableProperties(&cProps)
+	}
+	return props, nil
+}
@@ -0,0 +1,165 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// TableEntryType is the type of an entry added to a table.
+type TableEntryType uint
+
+const (
+	// TableEntryPut is a value written with Put.
+	TableEntryPut = TableEntryType(0)
+	// TableEntryDelete is a tombstone written with Delete.
+	TableEntryDelete = TableEntryType(1)
+	// TableEntrySingleDelete is a tombstone written with SingleDelete.
+	TableEntrySingleDelete = TableEntryType(2)
+	// TableEntryMerge is an operand written with Merge.
+	TableEntryMerge = TableEntryType(3)
+	// TableEntryRangeDeletion is a range tombstone written with DeleteRange.
+	TableEntryRangeDeletion = TableEntryType(4)
+	// TableEntryBlobIndex is a reference to a value stored in a blob file.
+	TableEntryBlobIndex = TableEntryType(5)
+	// TableEntryOther is any other entry. Entry types added by newer
+	// RocksDB versions, e.g. wide-column entities, are reported as
+	// TableEntryOther.
+	TableEntryOther = TableEntryType(6)
+)
+
+// TablePropertiesCollectorContext describes the table a
+// TablePropertiesCollector is created for.
+type TablePropertiesCollectorContext struct {
+	// ColumnFamilyID is the id of the column family of the table.
+	ColumnFamilyID uint32
+
+	// LevelAtCreation is the level the table is written to, or -1 if
+	// unknown.
+	LevelAtCreation int
+}
+
+// A TablePropertiesCollector collects user defined properties of a table
+// while it is written by a flush or compaction. Each collector is only
+// used for a single table from a single thread and so does not need to be
+// thread-safe.
+type TablePropertiesCollector interface {
+	// AddUserKey is called for each entry added to the table, in order.
+	AddUserKey(key, value []byte, entryType TableEntryType, seq uint64, fileSize uint64)
+
+	// Finish returns the properties which are stored in the table. They
+	// are returned in TableProperties.UserCollectedProperties.
+	Finish() map[string]string
+
+	// The name of the collector, for logging
+	Name() string
+}
+
+// A TablePropertiesCollectorFactory creates a new TablePropertiesCollector
+// for each table written by a flush or compaction.
+type TablePropertiesCollectorFactory interface {
+	// CreateTablePropertiesCollector returns the collector for a single
+	// table. Returning nil disables collecting for that table.
+	CreateTablePropertiesCollector(context TablePropertiesCollectorContext) TablePropertiesCollector
+
+	// The name of the factory, for logging
+	Name() string
+}
+
+// Hold references to table properties collector factories.
+var tablePropertiesCollectorFactories = NewCOWList()
+
+type tablePropertiesCollectorFactoryWrapper struct {
+	name    *C.char
+	factory TablePropertiesCollectorFactory
+}
+
+func registerTablePropertiesCollectorFactory(factory TablePropertiesCollectorFactory) int {
+	return tablePropertiesCollectorFactories.Append(tablePropertiesCollectorFactoryWrapper{C.CString(factory.Name()), factory})
+}
+
+// Hold references to the collectors of tables being written. Entries are
+// removed again once the table is finished, like compactionJobFilters.
+var (
+	tablePropertiesCollectors   sync.Map
+	tablePropertiesCollectorSeq int64
+)
+
+type tablePropertiesCollectorWrapper struct {
+	name      *C.char
+	collector TablePropertiesCollector
+}
+
+var emptyTablePropertiesCollectorName = C.CString("")
+
+//export gorocksdb_tablepropertiescollectorfactory_create_collector
+func gorocksdb_tablepropertiescollectorfactory_create_collector(idx int, cCFID C.uint32_t, cLevel C.int) int {
+	collector := tablePropertiesCollectorFactories.Get(idx).(tablePropertiesCollectorFactoryWrapper).factory.CreateTablePropertiesCollector(TablePropertiesCollectorContext{
+		ColumnFamilyID:  uint32(cCFID),
+		LevelAtCreation: int(cLevel),
+	})
+	if collector == nil {
+		return 0
+	}
+	// 0 is reserved for "no collector"
+	collectorIdx := int(atomic.AddInt64(&tablePropertiesCollectorSeq, 1))
+	tablePropertiesCollectors.Store(collectorIdx, tablePropertiesCollectorWrapper{C.CString(collector.Name()), collector})
+	return collectorIdx
+}
+
+//export gorocksdb_tablepropertiescollectorfactory_name
+func gorocksdb_tablepropertiescollectorfactory_name(idx int) *C.char {
+	return tablePropertiesCollectorFactories.Get(idx).(tablePropertiesCollectorFactoryWrapper).name
+}
+
+func loadTablePropertiesCollector(idx int) (tablePropertiesCollectorWrapper, bool) {
+	v, ok := tablePropertiesCollectors.Load(idx)
+	if !ok {
+		return tablePropertiesCollectorWrapper{}, false
+	}
+	return v.(tablePropertiesCollectorWrapper), true
+}
+
+//export gorocksdb_tablepropertiescollector_add_user_key
+func gorocksdb_tablepropertiescollector_add_user_key(idx int, cKey *C.char, cKeyLen C.size_t, cVal *C.char, cValLen C.size_t, cType C.int, cSeq C.uint64_t, cFileSize C.uint64_t) {
+	wrapper, ok := loadTablePropertiesCollector(idx)
+	if !ok {
+		return
+	}
+	wrapper.collector.AddUserKey(charToByte(cKey, cKeyLen), charToByte(cVal, cValLen), TableEntryType(cType), uint64(cSeq), uint64(cFileSize))
+}
+
+//export gorocksdb_tablepropertiescollector_finish
+func gorocksdb_tablepropertiescollector_finish(idx int, cProps unsafe.Pointer) {
+	wrapper, ok := loadTablePropertiesCollector(idx)
+	if !ok {
+		return
+	}
+	for k, v := range wrapper.collector.Finish() {
+		key, val := []byte(k), []byte(v)
+		C.gorocksdb_usercollectedproperties_add(cProps, byteToChar(key), C.size_t(len(key)), byteToChar(val), C.size_t(len(val)))
+	}
+}
+
+//export gorocksdb_tablepropertiescollector_name
+func gorocksdb_tablepropertiescollector_name(idx int) *C.char {
+	wrapper, ok := loadTablePropertiesCollector(idx)
+	if !ok {
+		return emptyTablePropertiesCollectorName
+	}
+	return wrapper.name
+}
+
+//export gorocksdb_tablepropertiescollector_destroy
+func gorocksdb_tablepropertiescollector_destroy(idx int) {
+	wrapper, ok := loadTablePropertiesCollector(idx)
+	if !ok {
+		return
+	}
+	tablePropertiesCollectors.Delete(idx)
+	C.free(unsafe.Pointer(wrapper.name))
+}
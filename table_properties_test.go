@@ -0,0 +1,108 @@
+package gorocksdb
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+type mockTablePropertiesCollector struct {
+	puts, deletes int
+	maxKey        []byte
+}
+
+func (c *mockTablePropertiesCollector) AddUserKey(key, value []byte, entryType TableEntryType, seq uint64, fileSize uint64) {
+	switch entryType {
+	case TableEntryPut:
+		c.puts++
+	case TableEntryDelete:
+		c.deletes++
+	}
+	c.maxKey = append(c.maxKey[:0], key...)
+}
+
+func (c *mockTablePropertiesCollector) Finish() map[string]string {
+	return map[string]string{
+		"test.puts":    strconv.Itoa(c.puts),
+		"test.deletes": strconv.Itoa(c.deletes),
+		"test.max-key": string(c.maxKey),
+	}
+}
+
+func (c *mockTablePropertiesCollector) Name() string { return "gorocksdb.test" }
+
+type mockTablePropertiesCollectorFactory struct {
+	mu     sync.Mutex
+	levels []int
+}
+
+func (f *mockTablePropertiesCollectorFactory) CreateTablePropertiesCollector(context TablePropertiesCollectorContext) TablePropertiesCollector {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.levels = append(f.levels, context.LevelAtCreation)
+	return &mockTablePropertiesCollector{}
+}
+
+func (f *mockTablePropertiesCollectorFactory) createdLevels() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]int(nil), f.levels...)
+}
+
+func (f *mockTablePropertiesCollectorFactory) Name() string { return "gorocksdb.test" }
+
+func TestTablePropertiesCollector(t *testing.T) {
+	factory := &mockTablePropertiesCollectorFactory{}
+	db := newTestDB(t, "TestTablePropertiesCollector", func(opts *Options) {
+		opts.AddTablePropertiesCollectorFactory(factory)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	fo := NewDefaultFlushOptions()
+	ensure.Nil(t, db.Put(wo, []byte("a1"), []byte("val")))
+	ensure.Nil(t, db.Put(wo, []byte("a2"), []byte("val")))
+	ensure.Nil(t, db.Flush(fo))
+	ensure.Nil(t, db.Put(wo, []byte("b1"), []byte("val")))
+	ensure.Nil(t, db.Delete(wo, []byte("b2")))
+	ensure.Nil(t, db.Flush(fo))
+	ensure.DeepEqual(t, factory.createdLevels(), []int{0, 0})
+
+	all, err := db.GetPropertiesOfAllTables()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(all), 2)
+	maxKeys := make(map[string]bool)
+	for _, props := range all {
+		maxKeys[props.UserCollectedProperties["test.max-key"]] = true
+		if props.UserCollectedProperties["test.max-key"] == "b2" {
+			ensure.DeepEqual(t, props.UserCollectedProperties["test.puts"], "1")
+			ensure.DeepEqual(t, props.UserCollectedProperties["test.deletes"], "1")
+			ensure.DeepEqual(t, props.NumDeletions, uint64(1))
+		}
+	}
+	ensure.DeepEqual(t, maxKeys, map[string]bool{"a2": true, "b2": true})
+
+	inRange, err := db.GetPropertiesOfTablesInRange([]Range{{Start: []byte("a"), Limit: []byte("a3")}})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(inRange), 1)
+	for _, props := range inRange {
+		ensure.DeepEqual(t, props.UserCollectedProperties["test.puts"], "2")
+	}
+
+	// skip tables by their user collected properties
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetTableFilter(func(props *TableProperties) bool {
+		return props.UserCollectedProperties["test.max-key"] != "a2"
+	})
+	iter := db.NewIterator(ro)
+	defer iter.Close()
+	var keys []string
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		keys = append(keys, string(iter.Key().Data()))
+	}
+	ensure.Nil(t, iter.Err())
+	ensure.DeepEqual(t, keys, []string{"b1"})
+}
@@ -2,12 +2,10 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
-import (
-	"errors"
-	"unsafe"
-)
+import "unsafe"
 
 // Transaction is used with TransactionDB for transaction support.
 type Transaction struct {
@@ -19,7 +17,9 @@ func NewNativeTransaction(c *C.rocksdb_transaction_t) *Transaction {
 	return &Transaction{c}
 }
 
-// Commit commits the transaction to the database.
+// Commit commits the transaction to the database. If the transaction
+// conflicts with another write, the error matches ErrBusy or ErrTryAgain
+// and the transaction can be retried.
 func (transaction *Transaction) Commit() error {
 	var (
 		cErr *C.char
@@ -27,7 +27,7 @@ func (transaction *Transaction) Commit() error {
 	C.rocksdb_transaction_commit(transaction.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -41,11 +41,91 @@ func (transaction *Transaction) Rollback() error {
 
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
 
+// SetSavePoint records the state of the transaction for future calls to
+// RollbackToSavePoint. May be called multiple times to set multiple save
+// points.
+func (transaction *Transaction) SetSavePoint() {
+	C.rocksdb_transaction_set_savepoint(transaction.c)
+}
+
+// RollbackToSavePoint undoes all operations in this transaction since the
+// most recent call to SetSavePoint and removes the most recent save point.
+// Returns an error if there is no previous call to SetSavePoint.
+func (transaction *Transaction) RollbackToSavePoint() error {
+	var (
+		cErr *C.char
+	)
+	C.rocksdb_transaction_rollback_to_savepoint(transaction.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// SetName sets the name of the transaction, which is required before
+// calling Prepare. The name must be unique among all transactions of the
+// database.
+func (transaction *Transaction) SetName(name string) error {
+	var (
+		cErr  *C.char
+		cName = C.CString(name)
+	)
+	defer C.free(unsafe.Pointer(cName))
+	C.rocksdb_transaction_set_name(transaction.c, cName, C.size_t(len(name)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// GetName returns the name of the transaction as given to SetName.
+func (transaction *Transaction) GetName() string {
+	var cLen C.size_t
+	cName := C.rocksdb_transaction_get_name(transaction.c, &cLen)
+	if cName == nil {
+		return ""
+	}
+	defer C.rocksdb_free(unsafe.Pointer(cName))
+	return string(charToByte(cName, cLen))
+}
+
+// Prepare performs the first phase of a two-phase commit. The transaction
+// must have been named with SetName. Once prepared, the transaction is
+// persisted in the write ahead log and survives a crash; it can be found
+// again with TransactionDB.GetAllPreparedTransactions and finished with
+// Commit or Rollback.
+func (transaction *Transaction) Prepare() error {
+	var (
+		cErr *C.char
+	)
+	C.rocksdb_transaction_prepare(transaction.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// GetWriteBatch returns a copy of the updates staged in the transaction.
+// The records can be inspected with WriteBatch.NewIterator.
+// The returned WriteBatch must be destroyed by the caller.
+func (transaction *Transaction) GetWriteBatch() *WriteBatch {
+	// the batch is owned by the transaction, so only the wrapper is freed.
+	cWbwi := C.rocksdb_transaction_get_writebatch_wi(transaction.c)
+	defer C.rocksdb_free(unsafe.Pointer(cWbwi))
+
+	var cSize C.size_t
+	cData := C.rocksdb_writebatch_wi_data(cWbwi, &cSize)
+	return NewNativeWriteBatch(C.rocksdb_writebatch_create_from(cData, cSize))
+}
+
 // Get returns the data associated with the key from the database given this transaction.
 func (transaction *Transaction) Get(opts *ReadOptions, key []byte) (*Slice, error) {
 	var (
@@ -58,7 +138,24 @@ func (transaction *Transaction) Get(opts *ReadOptions, key []byte) (*Slice, erro
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// GetCF returns the data associated with the key from the database and column family given this transaction.
+func (transaction *Transaction) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_transaction_get_cf(
+		transaction.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cValLen, &cErr,
+	)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return NewSlice(cValue, cValLen), nil
 }
@@ -75,7 +172,24 @@ func (transaction *Transaction) GetForUpdate(opts *ReadOptions, key []byte) (*Sl
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// GetForUpdateCF queries the data associated with the key and puts an exclusive lock on the key from the database and column family given this transaction.
+func (transaction *Transaction) GetForUpdateCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_transaction_get_for_update_cf(
+		transaction.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cValLen, C.uchar(byte(1)) /*exclusive*/, &cErr,
+	)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return NewSlice(cValue, cValLen), nil
 }
@@ -92,7 +206,60 @@ func (transaction *Transaction) Put(key, value []byte) error {
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// PutCF writes data associated with a key to the transaction and column family.
+func (transaction *Transaction) PutCF(cf *ColumnFamilyHandle, key, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_transaction_put_cf(
+		transaction.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr,
+	)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Merge merges the data associated with the key with the actual data in the
+// transaction.
+func (transaction *Transaction) Merge(key, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_transaction_merge(
+		transaction.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr,
+	)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// MergeCF merges the data associated with the key with the actual data in the
+// transaction and column family.
+func (transaction *Transaction) MergeCF(cf *ColumnFamilyHandle, key, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_transaction_merge_cf(
+		transaction.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr,
+	)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -106,7 +273,51 @@ func (transaction *Transaction) Delete(key []byte) error {
 	C.rocksdb_transaction_delete(transaction.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// DeleteCF removes the data associated with the key from the transaction and column family.
+func (transaction *Transaction) DeleteCF(cf *ColumnFamilyHandle, key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.rocksdb_transaction_delete_cf(transaction.c, cf.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// SingleDelete removes the data associated with the key from the
+// transaction, see DB.SingleDelete.
+func (transaction *Transaction) SingleDelete(key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.gorocksdb_transaction_singledelete(transaction.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// SingleDeleteCF removes the data associated with the key from the
+// transaction and column family, see DB.SingleDelete.
+func (transaction *Transaction) SingleDeleteCF(cf *ColumnFamilyHandle, key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.gorocksdb_transaction_singledelete_cf(transaction.c, cf.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -118,8 +329,23 @@ func (transaction *Transaction) NewIterator(opts *ReadOptions) *Iterator {
 		unsafe.Pointer(C.rocksdb_transaction_create_iterator(transaction.c, opts.c)))
 }
 
+// NewIteratorCF returns an Iterator over the database and column family
+// that uses the ReadOptions given.
+func (transaction *Transaction) NewIteratorCF(opts *ReadOptions, cf *ColumnFamilyHandle) *Iterator {
+	return NewNativeIterator(
+		unsafe.Pointer(C.rocksdb_transaction_create_iterator_cf(transaction.c, opts.c, cf.c)))
+}
+
 // Destroy deallocates the transaction object.
 func (transaction *Transaction) Destroy() {
 	C.rocksdb_transaction_destroy(transaction.c)
 	transaction.c = nil
 }
+
+// Free releases a Transaction returned by GetAllPreparedTransactions
+// without deallocating the transaction object, which is still owned by
+// the Transaction it was begun with.
+func (transaction *Transaction) Free() {
+	C.gorocksdb_transaction_free_handle(transaction.c)
+	transaction.c = nil
+}
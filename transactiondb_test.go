@@ -2,6 +2,7 @@ package gorocksdb
 
 import (
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/facebookgo/ensure"
@@ -122,6 +123,285 @@ func TestTransactionDBGetForUpdate(t *testing.T) {
 	}
 }
 
+func TestTransactionDBColumnFamilies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorockstransactiondb-TestTransactionDBColumnFamilies")
+	ensure.Nil(t, err)
+
+	givenNames := []string{"default", "guide"}
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissingColumnFamilies(true)
+	opts.SetCreateIfMissing(true)
+	transactionDBOpts := NewDefaultTransactionDBOptions()
+
+	db, cfh, err := OpenTransactionDbColumnFamilies(opts, transactionDBOpts, dir, givenNames, []*Options{opts, opts})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(cfh), 2)
+	cfh[0].Destroy()
+	cfh[1].Destroy()
+
+	db.Close()
+	db, cfh, err = OpenTransactionDbColumnFamilies(opts, transactionDBOpts, dir, givenNames, []*Options{opts, opts})
+	ensure.Nil(t, err)
+	defer db.Close()
+	defer cfh[0].Destroy()
+	defer cfh[1].Destroy()
+
+	var (
+		givenKey    = []byte("hello")
+		givenVal1   = []byte("world1")
+		givenVal2   = []byte("world2")
+		givenTxnKey = []byte("hello2")
+		wo          = NewDefaultWriteOptions()
+		ro          = NewDefaultReadOptions()
+		to          = NewDefaultTransactionOptions()
+	)
+
+	// writes are isolated per column family
+	ensure.Nil(t, db.PutCF(wo, cfh[0], givenKey, givenVal1))
+	ensure.Nil(t, db.PutCF(wo, cfh[1], givenKey, givenVal2))
+
+	v1, err := db.GetCF(ro, cfh[0], givenKey)
+	defer v1.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1.Data(), givenVal1)
+
+	v2, err := db.GetCF(ro, cfh[1], givenKey)
+	defer v2.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v2.Data(), givenVal2)
+
+	// transaction
+	txn := db.TransactionBegin(wo, to, nil)
+	defer txn.Destroy()
+	ensure.Nil(t, txn.PutCF(cfh[1], givenTxnKey, givenVal1))
+	ensure.Nil(t, txn.DeleteCF(cfh[1], givenKey))
+
+	v3, err := txn.GetForUpdateCF(ro, cfh[1], givenTxnKey)
+	defer v3.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v3.Data(), givenVal1)
+
+	iter := txn.NewIteratorCF(ro, cfh[1])
+	var actualKeys [][]byte
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		key := make([]byte, len(iter.Key().Data()))
+		copy(key, iter.Key().Data())
+		actualKeys = append(actualKeys, key)
+	}
+	ensure.Nil(t, iter.Err())
+	iter.Close()
+	ensure.DeepEqual(t, actualKeys, [][]byte{givenTxnKey})
+
+	ensure.Nil(t, txn.Commit())
+
+	v4, err := db.GetCF(ro, cfh[1], givenKey)
+	defer v4.Free()
+	ensure.Nil(t, err)
+	ensure.True(t, v4.Data() == nil)
+
+	// the default column family is untouched
+	v5, err := txn.GetCF(ro, cfh[0], givenKey)
+	defer v5.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v5.Data(), givenVal1)
+
+	// create a column family after opening
+	cf, err := db.CreateColumnFamily(opts, "other")
+	ensure.Nil(t, err)
+	defer cf.Destroy()
+	ensure.Nil(t, db.PutCF(wo, cf, givenKey, givenVal2))
+	ensure.Nil(t, db.DeleteCF(wo, cf, givenKey))
+}
+
+func TestTransactionSavePoint(t *testing.T) {
+	db := newTestTransactionDB(t, "TestTransactionSavePoint", nil)
+	defer db.Close()
+
+	var (
+		givenKey1 = []byte("hello1")
+		givenKey2 = []byte("hello2")
+		givenVal  = []byte("world")
+		wo        = NewDefaultWriteOptions()
+		ro        = NewDefaultReadOptions()
+		to        = NewDefaultTransactionOptions()
+	)
+
+	txn := db.TransactionBegin(wo, to, nil)
+	defer txn.Destroy()
+
+	// rolling back without a save point fails
+	ensure.NotNil(t, txn.RollbackToSavePoint())
+
+	ensure.Nil(t, txn.Put(givenKey1, givenVal))
+	txn.SetSavePoint()
+	ensure.Nil(t, txn.Put(givenKey2, givenVal))
+	ensure.Nil(t, txn.RollbackToSavePoint())
+	ensure.Nil(t, txn.Commit())
+
+	v1, err := db.Get(ro, givenKey1)
+	defer v1.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1.Data(), givenVal)
+
+	v2, err := db.Get(ro, givenKey2)
+	defer v2.Free()
+	ensure.Nil(t, err)
+	ensure.True(t, v2.Data() == nil)
+}
+
+func TestTransactionMerge(t *testing.T) {
+	var (
+		givenKey    = []byte("hello")
+		givenVal1   = []byte("foo")
+		givenVal2   = []byte("bar")
+		givenMerged = []byte("foobar")
+	)
+	merger := &mockMergeOperator{
+		fullMerge: func(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+			return append(append([]byte{}, existingValue...), operands[0]...), true
+		},
+	}
+	db := newTestTransactionDB(t, "TestTransactionMerge", func(opts *Options, transactionDBOpts *TransactionDBOptions) {
+		opts.SetMergeOperator(merger)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+	to := NewDefaultTransactionOptions()
+	ensure.Nil(t, db.Put(wo, givenKey, givenVal1))
+
+	txn := db.TransactionBegin(wo, to, nil)
+	defer txn.Destroy()
+	ensure.Nil(t, txn.Merge(givenKey, givenVal2))
+	ensure.Nil(t, txn.Commit())
+
+	v1, err := db.Get(ro, givenKey)
+	defer v1.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1.Data(), givenMerged)
+}
+
+func TestTransactionSingleDelete(t *testing.T) {
+	db := newTestTransactionDB(t, "TestTransactionSingleDelete", nil)
+	defer db.Close()
+
+	var (
+		givenKey = []byte("hello")
+		givenVal = []byte("world")
+		wo       = NewDefaultWriteOptions()
+		ro       = NewDefaultReadOptions()
+		to       = NewDefaultTransactionOptions()
+	)
+	ensure.Nil(t, db.Put(wo, givenKey, givenVal))
+
+	txn := db.TransactionBegin(wo, to, nil)
+	defer txn.Destroy()
+	ensure.Nil(t, txn.SingleDelete(givenKey))
+	ensure.Nil(t, txn.Commit())
+
+	v1, err := db.Get(ro, givenKey)
+	defer v1.Free()
+	ensure.Nil(t, err)
+	ensure.False(t, v1.Exists())
+}
+
+func TestTransactionTwoPhaseCommit(t *testing.T) {
+	db := newTestTransactionDB(t, "TestTransactionTwoPhaseCommit", nil)
+	defer db.Close()
+
+	var (
+		givenName = "txn1"
+		givenKey  = []byte("hello")
+		givenVal  = []byte("world")
+		wo        = NewDefaultWriteOptions()
+		ro        = NewDefaultReadOptions()
+		to        = NewDefaultTransactionOptions()
+	)
+
+	txn := db.TransactionBegin(wo, to, nil)
+	defer txn.Destroy()
+	ensure.Nil(t, txn.SetName(givenName))
+	ensure.DeepEqual(t, txn.GetName(), givenName)
+	ensure.Nil(t, txn.Put(givenKey, givenVal))
+
+	// inspect the staged writes
+	wb := txn.GetWriteBatch()
+	defer wb.Destroy()
+	iter := wb.NewIterator()
+	ensure.True(t, iter.Next())
+	record := iter.Record()
+	ensure.DeepEqual(t, record.Type, WriteBatchValueRecord)
+	ensure.DeepEqual(t, record.Key, givenKey)
+	ensure.DeepEqual(t, record.Value, givenVal)
+	ensure.False(t, iter.Next())
+
+	ensure.Nil(t, txn.Prepare())
+
+	prepared := db.GetAllPreparedTransactions()
+	ensure.DeepEqual(t, len(prepared), 1)
+	ensure.DeepEqual(t, prepared[0].GetName(), givenName)
+	// txn still owns the transaction
+	prepared[0].Free()
+
+	ensure.Nil(t, txn.Commit())
+	ensure.DeepEqual(t, len(db.GetAllPreparedTransactions()), 0)
+
+	v1, err := db.Get(ro, givenKey)
+	defer v1.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1.Data(), givenVal)
+}
+
+func TestTransactionRecoverPrepared(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorockstransactiondb-TestTransactionRecoverPrepared")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		givenName = "txn1"
+		givenKey  = []byte("hello")
+		givenVal  = []byte("world")
+		wo        = NewDefaultWriteOptions()
+		ro        = NewDefaultReadOptions()
+		to        = NewDefaultTransactionOptions()
+		opts      = NewDefaultOptions()
+		txnDBOpts = NewDefaultTransactionDBOptions()
+	)
+	opts.SetCreateIfMissing(true)
+
+	// prepare a transaction and close the database without committing it
+	db, err := OpenTransactionDb(opts, txnDBOpts, dir)
+	ensure.Nil(t, err)
+	txn := db.TransactionBegin(wo, to, nil)
+	ensure.Nil(t, txn.SetName(givenName))
+	ensure.Nil(t, txn.Put(givenKey, givenVal))
+	ensure.Nil(t, txn.Prepare())
+	txn.Destroy()
+	db.Close()
+
+	// the transaction is recovered from the write ahead log
+	db, err = OpenTransactionDb(opts, txnDBOpts, dir)
+	ensure.Nil(t, err)
+	defer db.Close()
+	v1, err := db.Get(ro, givenKey)
+	ensure.Nil(t, err)
+	ensure.False(t, v1.Exists())
+	v1.Free()
+
+	prepared := db.GetAllPreparedTransactions()
+	ensure.DeepEqual(t, len(prepared), 1)
+	ensure.DeepEqual(t, prepared[0].GetName(), givenName)
+	ensure.Nil(t, prepared[0].Commit())
+	prepared[0].Destroy()
+	ensure.DeepEqual(t, len(db.GetAllPreparedTransactions()), 0)
+
+	v2, err := db.Get(ro, givenKey)
+	defer v2.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v2.Data(), givenVal)
+}
+
 func newTestTransactionDB(t *testing.T, name string, applyOpts func(opts *Options, transactionDBOpts *TransactionDBOptions)) *TransactionDB {
 	dir, err := ioutil.TempDir("", "gorockstransactiondb-"+name)
 	ensure.Nil(t, err)
@@ -4,10 +4,50 @@ package gorocksdb
 
 import "C"
 import (
+	"context"
 	"reflect"
+	"sync"
 	"unsafe"
 )
 
+// runContext runs fn and calls cancel from another goroutine if ctx is done
+// before fn returned; cancel is never called after fn returned. If fn was
+// canceled and failed, ctx.Err() is returned instead of the error of fn.
+func runContext(ctx context.Context, fn func() error, cancel func()) error {
+	if ctx.Done() == nil {
+		return fn()
+	}
+	var (
+		mu       sync.Mutex
+		done     bool
+		canceled bool
+	)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			defer mu.Unlock()
+			if !done {
+				cancel()
+				canceled = true
+			}
+		case <-stop:
+		}
+	}()
+	err := fn()
+
+	mu.Lock()
+	done = true
+	wasCanceled := canceled
+	mu.Unlock()
+	if wasCanceled && err != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
 // btoi converts a bool value to int.
 func btoi(b bool) int {
 	if b {
@@ -0,0 +1,47 @@
+package gorocksdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestRunContext(t *testing.T) {
+	errStopped := errors.New("stopped")
+
+	// fn fails after it was canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	err := runContext(ctx, func() error {
+		cancel()
+		<-stopped
+		return errStopped
+	}, func() {
+		close(stopped)
+	})
+	ensure.DeepEqual(t, err, context.Canceled)
+
+	// fn finishes although it was canceled
+	ctx, cancel = context.WithCancel(context.Background())
+	stopped = make(chan struct{})
+	err = runContext(ctx, func() error {
+		cancel()
+		<-stopped
+		return nil
+	}, func() {
+		close(stopped)
+	})
+	ensure.Nil(t, err)
+
+	// cancel is not called once fn returned
+	ctx, cancel = context.WithCancel(context.Background())
+	err = runContext(ctx, func() error {
+		return errStopped
+	}, func() {
+		t.Error("cancel called after fn returned")
+	})
+	cancel()
+	ensure.DeepEqual(t, err, errStopped)
+}
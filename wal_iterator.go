@@ -3,10 +3,7 @@ package gorocksdb
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
 import "C"
-import (
-	"errors"
-	"unsafe"
-)
+import "unsafe"
 
 type WalIterator struct {
 	c *C.rocksdb_wal_iterator_t
@@ -29,7 +26,7 @@ func (iter *WalIterator) Err() error {
 	C.rocksdb_wal_iter_status(iter.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -74,6 +74,20 @@ func (wb *WriteBatch) DeleteCF(cf *ColumnFamilyHandle, key []byte) {
 	C.rocksdb_writebatch_delete_cf(wb.c, cf.c, cKey, C.size_t(len(key)))
 }
 
+// SingleDelete queues a single deletion of the data at key, see
+// DB.SingleDelete.
+func (wb *WriteBatch) SingleDelete(key []byte) {
+	cKey := byteToChar(key)
+	C.rocksdb_writebatch_singledelete(wb.c, cKey, C.size_t(len(key)))
+}
+
+// SingleDeleteCF queues a single deletion of the data at key in a column
+// family, see DB.SingleDelete.
+func (wb *WriteBatch) SingleDeleteCF(cf *ColumnFamilyHandle, key []byte) {
+	cKey := byteToChar(key)
+	C.rocksdb_writebatch_singledelete_cf(wb.c, cf.c, cKey, C.size_t(len(key)))
+}
+
 // DeleteRange deletes keys that are between [startKey, endKey)
 func (wb *WriteBatch) DeleteRange(startKey []byte, endKey []byte) {
 	cStartKey := byteToChar(startKey)
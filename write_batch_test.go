@@ -85,3 +85,27 @@ func TestWriteBatchIterator(t *testing.T) {
 	// there shouldn't be any left
 	ensure.False(t, iter.Next())
 }
+
+func TestWriteBatchSingleDelete(t *testing.T) {
+	var (
+		givenKey1 = []byte("key1")
+		givenVal1 = []byte("val1")
+	)
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.Put(givenKey1, givenVal1)
+	wb.SingleDelete(givenKey1)
+	ensure.DeepEqual(t, wb.Count(), 2)
+
+	iter := wb.NewIterator()
+	ensure.True(t, iter.Next())
+	ensure.DeepEqual(t, iter.Record().Type, WriteBatchValueRecord)
+
+	ensure.True(t, iter.Next())
+	record := iter.Record()
+	ensure.DeepEqual(t, record.Type, WriteBatchSingleDeletionRecord)
+	ensure.DeepEqual(t, record.Key, givenKey1)
+
+	// there shouldn't be any left
+	ensure.False(t, iter.Next())
+}
@@ -0,0 +1,226 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+import "C"
+import "unsafe"
+
+// WriteBatchWithIndex is a WriteBatch that additionally keeps a searchable
+// index of its updates, so that they can be read back with GetFromBatch,
+// GetFromBatchAndDB or an iterator created by NewIteratorWithBase before the
+// batch is written to the database.
+type WriteBatchWithIndex struct {
+	c *C.rocksdb_writebatch_wi_t
+}
+
+// NewWriteBatchWithIndex creates a WriteBatchWithIndex object.
+// reservedBytes is the initial capacity of the underlying batch. If
+// overwriteKey is true, the index keeps only the latest update of each key,
+// which is required for the read-your-own-writes iterator to be precise.
+func NewWriteBatchWithIndex(reservedBytes int, overwriteKey bool) *WriteBatchWithIndex {
+	return NewNativeWriteBatchWithIndex(C.rocksdb_writebatch_wi_create(C.size_t(reservedBytes), boolToChar(overwriteKey)))
+}
+
+// NewNativeWriteBatchWithIndex creates a WriteBatchWithIndex object.
+func NewNativeWriteBatchWithIndex(c *C.rocksdb_writebatch_wi_t) *WriteBatchWithIndex {
+	return &WriteBatchWithIndex{c}
+}
+
+// Put queues a key-value pair.
+func (wb *WriteBatchWithIndex) Put(key, value []byte) {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	C.rocksdb_writebatch_wi_put(wb.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)))
+}
+
+// PutCF queues a key-value pair in a column family.
+func (wb *WriteBatchWithIndex) PutCF(cf *ColumnFamilyHandle, key, value []byte) {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	C.rocksdb_writebatch_wi_put_cf(wb.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)))
+}
+
+// PutLogData appends a blob of arbitrary size to the records in this batch.
+func (wb *WriteBatchWithIndex) PutLogData(blob []byte) {
+	cBlob := byteToChar(blob)
+	C.rocksdb_writebatch_wi_put_log_data(wb.c, cBlob, C.size_t(len(blob)))
+}
+
+// Merge queues a merge of "value" with the existing value of "key".
+func (wb *WriteBatchWithIndex) Merge(key, value []byte) {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	C.rocksdb_writebatch_wi_merge(wb.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)))
+}
+
+// MergeCF queues a merge of "value" with the existing value of "key" in a
+// column family.
+func (wb *WriteBatchWithIndex) MergeCF(cf *ColumnFamilyHandle, key, value []byte) {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	C.rocksdb_writebatch_wi_merge_cf(wb.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)))
+}
+
+// Delete queues a deletion of the data at key.
+func (wb *WriteBatchWithIndex) Delete(key []byte) {
+	cKey := byteToChar(key)
+	C.rocksdb_writebatch_wi_delete(wb.c, cKey, C.size_t(len(key)))
+}
+
+// DeleteCF queues a deletion of the data at key in a column family.
+func (wb *WriteBatchWithIndex) DeleteCF(cf *ColumnFamilyHandle, key []byte) {
+	cKey := byteToChar(key)
+	C.rocksdb_writebatch_wi_delete_cf(wb.c, cf.c, cKey, C.size_t(len(key)))
+}
+
+// errDeleteRangeNotSupported is returned by the DeleteRange methods, RocksDB
+// does not support range deletions in a WriteBatchWithIndex.
+var errDeleteRangeNotSupported = &Error{
+	Code: CodeNotSupported,
+	msg:  "Not implemented: DeleteRange is not supported by WriteBatchWithIndex",
+}
+
+// DeleteRange always returns an error matching ErrNotSupported, range
+// deletions cannot be indexed. Use a WriteBatch or DB.DeleteRange instead.
+func (wb *WriteBatchWithIndex) DeleteRange(startKey []byte, endKey []byte) error {
+	return errDeleteRangeNotSupported
+}
+
+// DeleteRangeCF always returns an error matching ErrNotSupported, like
+// DeleteRange.
+func (wb *WriteBatchWithIndex) DeleteRangeCF(cf *ColumnFamilyHandle, startKey []byte, endKey []byte) error {
+	return errDeleteRangeNotSupported
+}
+
+// GetFromBatch returns the data associated with the key from the batch only.
+// Merges are resolved with the merge operator of opts.
+func (wb *WriteBatchWithIndex) GetFromBatch(opts *Options, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_writebatch_wi_get_from_batch(wb.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// GetFromBatchCF returns the data associated with the key in the column
+// family from the batch only.
+func (wb *WriteBatchWithIndex) GetFromBatchCF(opts *Options, cf *ColumnFamilyHandle, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_writebatch_wi_get_from_batch_cf(wb.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// GetFromBatchAndDB returns the data associated with the key from the batch,
+// falling back to the database if the batch holds no update for the key.
+func (wb *WriteBatchWithIndex) GetFromBatchAndDB(db *DB, opts *ReadOptions, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_writebatch_wi_get_from_batch_and_db(wb.c, db.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// GetFromBatchAndDBCF returns the data associated with the key in the column
+// family from the batch, falling back to the database if the batch holds no
+// update for the key.
+func (wb *WriteBatchWithIndex) GetFromBatchAndDBCF(db *DB, opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_writebatch_wi_get_from_batch_and_db_cf(wb.c, db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// NewIteratorWithBase returns an Iterator that overlays the updates of the
+// batch on baseIter, which is usually created by DB.NewIterator.
+// The batch takes ownership of baseIter, which must not be used or closed
+// afterwards. Close the returned Iterator instead.
+func (wb *WriteBatchWithIndex) NewIteratorWithBase(baseIter *Iterator) *Iterator {
+	cIter := C.rocksdb_writebatch_wi_create_iterator_with_base(wb.c, baseIter.c)
+	baseIter.c = nil
+	return NewNativeIterator(unsafe.Pointer(cIter))
+}
+
+// NewIteratorWithBaseCF returns an Iterator that overlays the updates of the
+// batch in the column family on baseIter, which is usually created by
+// DB.NewIteratorCF. The batch takes ownership of baseIter.
+func (wb *WriteBatchWithIndex) NewIteratorWithBaseCF(baseIter *Iterator, cf *ColumnFamilyHandle) *Iterator {
+	cIter := C.rocksdb_writebatch_wi_create_iterator_with_base_cf(wb.c, baseIter.c, cf.c)
+	baseIter.c = nil
+	return NewNativeIterator(unsafe.Pointer(cIter))
+}
+
+// SetSavePoint records the state of the batch for future calls to
+// RollbackToSavePoint.
+func (wb *WriteBatchWithIndex) SetSavePoint() {
+	C.rocksdb_writebatch_wi_set_save_point(wb.c)
+}
+
+// RollbackToSavePoint removes all updates since the most recent call to
+// SetSavePoint and removes the most recent save point.
+func (wb *WriteBatchWithIndex) RollbackToSavePoint() error {
+	var cErr *C.char
+	C.rocksdb_writebatch_wi_rollback_to_save_point(wb.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Data returns the serialized version of this batch.
+func (wb *WriteBatchWithIndex) Data() []byte {
+	var cSize C.size_t
+	cValue := C.rocksdb_writebatch_wi_data(wb.c, &cSize)
+	return charToByte(cValue, cSize)
+}
+
+// Count returns the number of updates in the batch.
+func (wb *WriteBatchWithIndex) Count() int {
+	return int(C.rocksdb_writebatch_wi_count(wb.c))
+}
+
+// NewIterator returns a iterator to iterate over the records in the batch.
+func (wb *WriteBatchWithIndex) NewIterator() *WriteBatchIterator {
+	data := wb.Data()
+	if len(data) < 8+4 {
+		return &WriteBatchIterator{}
+	}
+	return &WriteBatchIterator{data: data[12:]}
+}
+
+// Clear removes all the enqueued Put and Deletes.
+func (wb *WriteBatchWithIndex) Clear() {
+	C.rocksdb_writebatch_wi_clear(wb.c)
+}
+
+// Destroy deallocates the WriteBatchWithIndex object.
+func (wb *WriteBatchWithIndex) Destroy() {
+	C.rocksdb_writebatch_wi_destroy(wb.c)
+	wb.c = nil
+}
@@ -0,0 +1,90 @@
+package gorocksdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestWriteBatchWithIndex(t *testing.T) {
+	db := newTestDB(t, "TestWriteBatchWithIndex", nil)
+	defer db.Close()
+
+	var (
+		givenKey1 = []byte("key1")
+		givenVal1 = []byte("val1")
+		givenKey2 = []byte("key2")
+		givenKey3 = []byte("key3")
+		givenVal3 = []byte("val3")
+		wo        = NewDefaultWriteOptions()
+		ro        = NewDefaultReadOptions()
+		opts      = NewDefaultOptions()
+	)
+	ensure.Nil(t, db.Put(wo, givenKey2, []byte("foo")))
+	ensure.Nil(t, db.Put(wo, givenKey3, givenVal3))
+
+	// create and fill the write batch
+	wb := NewWriteBatchWithIndex(0, true)
+	defer wb.Destroy()
+	wb.Put(givenKey1, givenVal1)
+	wb.Delete(givenKey2)
+	ensure.DeepEqual(t, wb.Count(), 2)
+
+	// read back from the batch only
+	v1, err := wb.GetFromBatch(opts, givenKey1)
+	defer v1.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1.Data(), givenVal1)
+
+	v3, err := wb.GetFromBatch(opts, givenKey3)
+	defer v3.Free()
+	ensure.Nil(t, err)
+	ensure.True(t, v3.Data() == nil)
+
+	// read back from the batch and the db
+	v2, err := wb.GetFromBatchAndDB(db, ro, givenKey2)
+	defer v2.Free()
+	ensure.Nil(t, err)
+	ensure.True(t, v2.Data() == nil)
+
+	v3, err = wb.GetFromBatchAndDB(db, ro, givenKey3)
+	defer v3.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v3.Data(), givenVal3)
+
+	// iterate over the batch overlaid on the db
+	iter := wb.NewIteratorWithBase(db.NewIterator(ro))
+	var actualKeys [][]byte
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		key := make([]byte, 4)
+		copy(key, iter.Key().Data())
+		actualKeys = append(actualKeys, key)
+	}
+	ensure.Nil(t, iter.Err())
+	iter.Close()
+	ensure.DeepEqual(t, actualKeys, [][]byte{givenKey1, givenKey3})
+
+	// perform the batch
+	ensure.Nil(t, db.WriteWithIndex(wo, wb))
+
+	v4, err := db.Get(ro, givenKey1)
+	defer v4.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v4.Data(), givenVal1)
+
+	v5, err := db.Get(ro, givenKey2)
+	defer v5.Free()
+	ensure.Nil(t, err)
+	ensure.True(t, v5.Data() == nil)
+}
+
+func TestWriteBatchWithIndexDeleteRange(t *testing.T) {
+	wb := NewWriteBatchWithIndex(0, true)
+	defer wb.Destroy()
+	wb.Put([]byte("key1"), []byte("val1"))
+
+	err := wb.DeleteRange([]byte("key1"), []byte("key2"))
+	ensure.True(t, errors.Is(err, ErrNotSupported))
+	ensure.DeepEqual(t, wb.Count(), 1)
+}